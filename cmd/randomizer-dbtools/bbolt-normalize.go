@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bboltNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rename groups in a bbolt database to their normalized names",
+	Long: `Rename groups in a bbolt database to their normalized names.
+
+The randomizer looks up groups by case folded, NFC-normalized names. Groups
+saved by older versions of the randomizer may not be accessible until they are
+renamed to this form.
+
+If any groups in a channel would share the same normalized name, this command
+reports the collisions and makes no changes. Delete or rename the conflicting
+groups and try again.`,
+	Run: runBboltNormalize,
+}
+
+var bboltNormalizeDryRun bool
+
+func init() {
+	bboltNormalizeCmd.Flags().BoolVarP(
+		&bboltNormalizeDryRun,
+		"dry-run", "n", false,
+		"report the required changes without making them",
+	)
+
+	bboltCmd.AddCommand(bboltNormalizeCmd)
+}
+
+func runBboltNormalize(cmd *cobra.Command, args []string) {
+	db := getBolt()
+	defer db.Close()
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		var keys []groupKey
+		err := tx.ForEach(func(partition []byte, b *bolt.Bucket) error {
+			return b.ForEach(func(group, value []byte) error {
+				if value != nil {
+					keys = append(keys, groupKey{string(partition), string(group)})
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		plan := planNormalization(keys)
+		plan.report(os.Stdout)
+		if len(plan.Collisions) > 0 {
+			return fmt.Errorf("found %d collisions", len(plan.Collisions))
+		}
+		if bboltNormalizeDryRun {
+			return nil
+		}
+
+		for key, name := range plan.Renames {
+			bucket := tx.Bucket([]byte(key.Partition))
+			value := bytes.Clone(bucket.Get([]byte(key.Group)))
+			if err := bucket.Put([]byte(name), value); err != nil {
+				return fmt.Errorf("writing %q in %q: %w", name, key.Partition, err)
+			}
+			if err := bucket.Delete([]byte(key.Group)); err != nil {
+				return fmt.Errorf("deleting %q in %q: %w", key.Group, key.Partition, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "normalization failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !bboltNormalizeDryRun {
+		fmt.Println("normalization complete")
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bboltCmd = &cobra.Command{
+	Use:   "bbolt",
+	Short: "Work with bbolt-based randomizer stores",
+}
+
+var bboltFile string
+
+func init() {
+	bboltCmd.PersistentFlags().StringVarP(
+		&bboltFile,
+		"file", "f", "randomizer.db",
+		"location of the bbolt database file to work with",
+	)
+
+	rootCmd.AddCommand(bboltCmd)
+}
+
+func getBolt() *bolt.DB {
+	db, err := bolt.Open(bboltFile, os.ModePerm&0644, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not open bbolt database: %v\n", err)
+		os.Exit(2)
+	}
+	return db
+}
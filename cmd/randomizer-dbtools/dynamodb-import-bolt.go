@@ -1,16 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/gob"
 	"fmt"
 	"os"
 
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/spf13/cobra"
 	bolt "go.etcd.io/bbolt"
+
+	"github.com/featherbread/randomizer/internal/store/bbolt"
+	"github.com/featherbread/randomizer/internal/store/dynamodb"
 )
 
 var dynamoImportBoltCmd = &cobra.Command{
@@ -38,6 +37,8 @@ func init() {
 }
 
 func runDynamoDBImportBolt(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
 	boltDB, err := bolt.Open(boltDBFile, os.ModePerm&0644, nil)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "could not open Bolt database: %v\n", err)
@@ -46,34 +47,11 @@ func runDynamoDBImportBolt(cmd *cobra.Command, args []string) {
 
 	dynamoDB := getDynamoDB()
 
-	writeRequests := make([]types.WriteRequest, 0)
+	var partitions []string
 	err = boltDB.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(func(partition []byte, b *bolt.Bucket) error {
-			return b.ForEach(func(group, itemsGob []byte) error {
-				var (
-					partitionStr = string(partition)
-					groupStr     = string(group)
-				)
-
-				var items []string
-				decoder := gob.NewDecoder(bytes.NewReader(itemsGob))
-				err := decoder.Decode(&items)
-				if err != nil {
-					return fmt.Errorf("decoding items for %q in %q: %w", groupStr, partitionStr, err)
-				}
-
-				writeRequests = append(writeRequests, types.WriteRequest{
-					PutRequest: &types.PutRequest{
-						Item: map[string]types.AttributeValue{
-							"Partition": &types.AttributeValueMemberS{Value: partitionStr},
-							"Group":     &types.AttributeValueMemberS{Value: groupStr},
-							"Items":     &types.AttributeValueMemberSS{Value: items},
-						},
-					},
-				})
-
-				return nil
-			})
+		return tx.ForEach(func(partition []byte, _ *bolt.Bucket) error {
+			partitions = append(partitions, string(partition))
+			return nil
 		})
 	})
 	if err != nil {
@@ -81,15 +59,66 @@ func runDynamoDBImportBolt(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	_, err = dynamoDB.BatchWriteItem(context.Background(), &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{
-			dynamoDBTable: writeRequests,
-		},
-	})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "could not write to DynamoDB: %v\n", err)
-		os.Exit(1)
+	for _, partition := range partitions {
+		src, err := bbolt.New(boltDB, partition)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not open %q in Bolt DB: %v\n", partition, err)
+			os.Exit(1)
+		}
+		dst, err := dynamodb.New(dynamoDB, dynamoDBTable, partition)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not open %q in DynamoDB: %v\n", partition, err)
+			os.Exit(1)
+		}
+
+		groups, err := src.ListGroups(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read groups in %q from Bolt DB: %v\n", partition, err)
+			os.Exit(1)
+		}
+		for name, group := range groups {
+			if err := dst.PutGroup(ctx, name, group); err != nil {
+				fmt.Fprintf(os.Stderr, "could not write to DynamoDB: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		aliases, err := src.ListAliases(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read aliases in %q from Bolt DB: %v\n", partition, err)
+			os.Exit(1)
+		}
+		for alias, group := range aliases {
+			if err := dst.PutAlias(ctx, alias, group); err != nil {
+				fmt.Fprintf(os.Stderr, "could not write to DynamoDB: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		away, err := src.ListAway(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read away options in %q from Bolt DB: %v\n", partition, err)
+			os.Exit(1)
+		}
+		for option, until := range away {
+			if err := dst.PutAway(ctx, option, until); err != nil {
+				fmt.Fprintf(os.Stderr, "could not write to DynamoDB: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		token, err := src.GetBotToken(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read bot token in %q from Bolt DB: %v\n", partition, err)
+			os.Exit(1)
+		}
+		if token != "" {
+			if err := dst.PutBotToken(ctx, token); err != nil {
+				fmt.Fprintf(os.Stderr, "could not write to DynamoDB: %v\n", err)
+				os.Exit(1)
+			}
+		}
 	}
 
-	fmt.Println("batch write sent")
+	fmt.Println("import complete")
 }
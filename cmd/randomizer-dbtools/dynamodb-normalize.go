@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/spf13/cobra"
+)
+
+var dynamoNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rename groups in a DynamoDB table to their normalized names",
+	Long: `Rename groups in a DynamoDB table to their normalized names.
+
+The randomizer looks up groups by case folded, NFC-normalized names. Groups
+saved by older versions of the randomizer may not be accessible until they are
+renamed to this form.
+
+If any groups in a channel would share the same normalized name, this command
+reports the collisions and makes no changes. Delete or rename the conflicting
+groups and try again.
+
+Each group is renamed in its own transaction, so a failed run may leave some
+groups renamed and others not. It's safe to run this command again.`,
+	Run: runDynamoDBNormalize,
+}
+
+var dynamoNormalizeDryRun bool
+
+func init() {
+	dynamoNormalizeCmd.Flags().BoolVarP(
+		&dynamoNormalizeDryRun,
+		"dry-run", "n", false,
+		"report the required changes without making them",
+	)
+
+	dynamoDBCmd.AddCommand(dynamoNormalizeCmd)
+}
+
+func runDynamoDBNormalize(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	db := getDynamoDB()
+
+	var (
+		keys  []groupKey
+		items = make(map[groupKey]map[string]types.AttributeValue)
+	)
+	paginator := dynamodb.NewScanPaginator(db, &dynamodb.ScanInput{TableName: &dynamoDBTable})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not scan table: %v\n", err)
+			os.Exit(1)
+		}
+		for _, item := range page.Items {
+			partition, pok := item["Partition"].(*types.AttributeValueMemberS)
+			group, gok := item["Group"].(*types.AttributeValueMemberS)
+			if !pok || !gok {
+				fmt.Fprintf(os.Stderr, "invalid key types in item: %v\n", item)
+				os.Exit(1)
+			}
+			key := groupKey{partition.Value, group.Value}
+			keys = append(keys, key)
+			items[key] = item
+		}
+	}
+
+	plan := planNormalization(keys)
+	plan.report(os.Stdout)
+	if len(plan.Collisions) > 0 {
+		fmt.Fprintf(os.Stderr, "normalization failed: found %d collisions\n", len(plan.Collisions))
+		os.Exit(1)
+	}
+	if dynamoNormalizeDryRun {
+		return
+	}
+
+	for _, key := range sortedGroupKeys(plan.Renames) {
+		item := items[key]
+		item["Group"] = &types.AttributeValueMemberS{Value: plan.Renames[key]}
+		_, err := db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				{
+					Put: &types.Put{
+						TableName:           &dynamoDBTable,
+						Item:                item,
+						ConditionExpression: aws.String("attribute_not_exists(#group)"),
+						ExpressionAttributeNames: map[string]string{
+							"#group": "Group",
+						},
+					},
+				},
+				{
+					Delete: &types.Delete{
+						TableName: &dynamoDBTable,
+						Key: map[string]types.AttributeValue{
+							"Partition": &types.AttributeValueMemberS{Value: key.Partition},
+							"Group":     &types.AttributeValueMemberS{Value: key.Group},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not rename %q in %q: %v\n", key.Group, key.Partition, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println("normalization complete")
+}
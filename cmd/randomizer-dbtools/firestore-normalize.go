@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spf13/cobra"
+)
+
+var firestoreNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rename groups in a Firestore database to their normalized names",
+	Long: `Rename groups in a Firestore database to their normalized names.
+
+The randomizer looks up groups by case folded, NFC-normalized names. Groups
+saved by older versions of the randomizer may not be accessible until they are
+renamed to this form.
+
+If any groups in a channel would share the same normalized name, this command
+reports the collisions and makes no changes. Delete or rename the conflicting
+groups and try again.
+
+Each group is renamed in its own transaction, so a failed run may leave some
+groups renamed and others not. It's safe to run this command again.`,
+	Run: runFirestoreNormalize,
+}
+
+var firestoreNormalizeDryRun bool
+
+func init() {
+	firestoreNormalizeCmd.Flags().BoolVarP(
+		&firestoreNormalizeDryRun,
+		"dry-run", "n", false,
+		"report the required changes without making them",
+	)
+
+	firestoreCmd.AddCommand(firestoreNormalizeCmd)
+}
+
+func runFirestoreNormalize(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	client := getFirestore()
+	defer client.Close()
+
+	collections, err := client.Collections(ctx).GetAll()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not list collections: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Each top-level collection holds the groups of one partition. Documents
+	// that only exist as parents of subcollections, like the ones holding away
+	// options, aren't returned by queries, and don't need renaming.
+	var keys []groupKey
+	for _, collection := range collections {
+		docs, err := collection.Select().Documents(ctx).GetAll()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not list documents in %q: %v\n", collection.ID, err)
+			os.Exit(1)
+		}
+		for _, doc := range docs {
+			keys = append(keys, groupKey{collection.ID, doc.Ref.ID})
+		}
+	}
+
+	plan := planNormalization(keys)
+	plan.report(os.Stdout)
+	if len(plan.Collisions) > 0 {
+		fmt.Fprintf(os.Stderr, "normalization failed: found %d collisions\n", len(plan.Collisions))
+		os.Exit(1)
+	}
+	if firestoreNormalizeDryRun {
+		return
+	}
+
+	for _, key := range sortedGroupKeys(plan.Renames) {
+		from := client.Collection(key.Partition).Doc(key.Group)
+		to := client.Collection(key.Partition).Doc(plan.Renames[key])
+		err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			snapshot, err := tx.Get(from)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(to, snapshot.Data()); err != nil {
+				return err
+			}
+			return tx.Delete(from, firestore.Exists)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not rename %q in %q: %v\n", key.Group, key.Partition, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println("normalization complete")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spf13/cobra"
+)
+
+var firestoreCmd = &cobra.Command{
+	Use:   "firestore",
+	Short: "Work with Firestore-based randomizer stores",
+	Long: `Work with Firestore-based randomizer stores.
+
+Google Cloud credentials are read from the environment as described at
+https://cloud.google.com/docs/authentication/application-default-credentials.`,
+}
+
+var (
+	firestoreProject  string
+	firestoreDatabase string
+)
+
+func init() {
+	firestoreCmd.PersistentFlags().StringVarP(
+		&firestoreProject,
+		"project", "p", os.Getenv("FIRESTORE_PROJECT_ID"),
+		"ID of the Google Cloud project to work with",
+	)
+
+	firestoreCmd.PersistentFlags().StringVarP(
+		&firestoreDatabase,
+		"database", "d", cmp.Or(os.Getenv("FIRESTORE_DATABASE_ID"), firestore.DefaultDatabaseID),
+		"ID of the Firestore database to work with",
+	)
+
+	rootCmd.AddCommand(firestoreCmd)
+}
+
+func getFirestore() *firestore.Client {
+	if firestoreProject == "" {
+		fmt.Fprintln(os.Stderr, "missing Google Cloud project ID")
+		os.Exit(2)
+	}
+	client, err := firestore.NewClientWithDatabase(context.Background(), firestoreProject, firestoreDatabase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create Firestore client: %v\n", err)
+		os.Exit(2)
+	}
+	return client
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// groupKey identifies a single group in a randomizer store.
+type groupKey struct {
+	Partition string
+	Group     string
+}
+
+// normalizationPlan describes the changes required to migrate a store's group
+// names to the form given by [randomizer.NormalizeGroupName].
+type normalizationPlan struct {
+	// Renames maps the keys of groups that must be renamed to their normalized
+	// names.
+	Renames map[groupKey]string
+	// Collisions maps normalized keys to the groups that would share them after
+	// normalization. It's unsafe to rename any of these groups without deciding
+	// which one to keep.
+	Collisions map[groupKey][]string
+}
+
+func planNormalization(keys []groupKey) normalizationPlan {
+	plan := normalizationPlan{
+		Renames:    make(map[groupKey]string),
+		Collisions: make(map[groupKey][]string),
+	}
+
+	sources := make(map[groupKey][]string)
+	for _, key := range keys {
+		// Keys with the "/" prefix reserved for flags hold other kinds of records,
+		// like aliases, which were created with normalized names.
+		if strings.HasPrefix(key.Group, "/") {
+			continue
+		}
+		normalized := groupKey{key.Partition, randomizer.NormalizeGroupName(key.Group)}
+		sources[normalized] = append(sources[normalized], key.Group)
+	}
+
+	for normalized, groups := range sources {
+		if len(groups) > 1 {
+			slices.Sort(groups)
+			plan.Collisions[normalized] = groups
+			continue
+		}
+		if groups[0] != normalized.Group {
+			plan.Renames[groupKey{normalized.Partition, groups[0]}] = normalized.Group
+		}
+	}
+
+	return plan
+}
+
+func (p normalizationPlan) report(w io.Writer) {
+	for _, key := range sortedGroupKeys(p.Collisions) {
+		fmt.Fprintf(w, "collision in %q: %q would all become %q\n",
+			key.Partition, p.Collisions[key], key.Group)
+	}
+	for _, key := range sortedGroupKeys(p.Renames) {
+		fmt.Fprintf(w, "rename in %q: %q to %q\n",
+			key.Partition, key.Group, p.Renames[key])
+	}
+}
+
+func sortedGroupKeys[V any](m map[groupKey]V) []groupKey {
+	return slices.SortedFunc(maps.Keys(m), func(a, b groupKey) int {
+		return cmp.Or(cmp.Compare(a.Partition, b.Partition), cmp.Compare(a.Group, b.Group))
+	})
+}
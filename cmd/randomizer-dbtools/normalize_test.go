@@ -0,0 +1,88 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPlanNormalization(t *testing.T) {
+	testCases := []struct {
+		description    string
+		keys           []groupKey
+		wantRenames    map[groupKey]string
+		wantCollisions map[groupKey][]string
+	}{
+		{
+			description: "already normalized",
+			keys:        []groupKey{{"C1", "lunch"}, {"C1", "caf\u00e9"}},
+		},
+		{
+			description: "case and Unicode form",
+			keys:        []groupKey{{"C1", "Lunch"}, {"C1", "cafe\u0301"}, {"C1", "STRASSE"}},
+			wantRenames: map[groupKey]string{
+				{"C1", "Lunch"}:      "lunch",
+				{"C1", "cafe\u0301"}: "caf\u00e9",
+				{"C1", "STRASSE"}:    "strasse",
+			},
+		},
+		{
+			description: "unnormalized names colliding with each other",
+			keys:        []groupKey{{"C1", "SNACKS"}, {"C1", "Snacks"}, {"C1", "Lunch"}},
+			wantRenames: map[groupKey]string{
+				{"C1", "Lunch"}: "lunch",
+			},
+			wantCollisions: map[groupKey][]string{
+				{"C1", "snacks"}: {"SNACKS", "Snacks"},
+			},
+		},
+		{
+			description: "unnormalized name colliding with a normalized one",
+			keys:        []groupKey{{"C1", "snacks"}, {"C1", "Snacks"}},
+			wantCollisions: map[groupKey][]string{
+				{"C1", "snacks"}: {"Snacks", "snacks"},
+			},
+		},
+		{
+			description: "same name in different partitions",
+			keys:        []groupKey{{"C1", "Snacks"}, {"C2", "snacks"}, {"C3", "SNACKS"}},
+			wantRenames: map[groupKey]string{
+				{"C1", "Snacks"}: "snacks",
+				{"C3", "SNACKS"}: "snacks",
+			},
+		},
+		{
+			description: "records under flag prefixes",
+			keys:        []groupKey{{"C1", "/alias/Lunch"}, {"C1", "/away/Alice"}, {"C1", "/bottoken"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			plan := planNormalization(tc.keys)
+			if !maps.Equal(plan.Renames, tc.wantRenames) {
+				t.Errorf("got renames %q, want %q", plan.Renames, tc.wantRenames)
+			}
+			if len(plan.Collisions) != len(tc.wantCollisions) {
+				t.Fatalf("got collisions %q, want %q", plan.Collisions, tc.wantCollisions)
+			}
+			for key, want := range tc.wantCollisions {
+				if got := plan.Collisions[key]; !slices.Equal(got, want) {
+					t.Errorf("got collision %q for %v, want %q", got, key, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizationReport(t *testing.T) {
+	plan := planNormalization([]groupKey{{"C1", "Snacks"}, {"C1", "snacks"}, {"C2", "Lunch"}})
+	var out strings.Builder
+	plan.report(&out)
+	want := `collision in "C1": ["Snacks" "snacks"] would all become "snacks"` + "\n" +
+		`rename in "C2": "Lunch" to "lunch"` + "\n"
+	if out.String() != want {
+		t.Errorf("got report:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -12,10 +12,18 @@
 // the randomizer. Note that this allows the demo CLI to exhibit behaviors not
 // normally possible with the slash command, such as randomizing or storing
 // options containing whitespace.
+//
+// With -i as its only argument, the demo CLI instead starts an interactive
+// session that reads one invocation per line, with line editing and history
+// when run in a terminal. Each line is split into arguments with shell-style
+// quoting, and output is written as plain text rather than mrkdwn. The
+// ":partition NAME" command switches to the groups of another partition, so a
+// single session can manage the groups of any channel in the configured store.
 package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -30,12 +38,41 @@ func main() {
 		os.Exit(2)
 	}
 
-	app := randomizer.NewApp(os.Args[0], storeFactory("Groups"))
+	rules, err := randomizer.RulesFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to configure group rules: %v\n", err)
+		os.Exit(2)
+	}
+
+	options := []randomizer.Option{
+		randomizer.WithRules(rules),
+		randomizer.WithUser(os.Getenv("USER")),
+	}
+
+	if len(os.Args) == 2 && os.Args[1] == "-i" {
+		r := &repl{
+			name:         os.Args[0],
+			storeFactory: storeFactory,
+			options:      options,
+			partition:    "Groups",
+		}
+		if err := r.runInteractive(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to run interactive session: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app := randomizer.NewApp(os.Args[0], storeFactory("Groups"), options...)
 	result, err := app.Main(context.Background(), os.Args[1:])
 	if err != nil {
-		err := err.(randomizer.Error)
-		fmt.Fprintln(os.Stderr, err.HelpText())
-		fmt.Fprintf(os.Stderr, "(%v)\n", err)
+		var rerr randomizer.Error
+		if errors.As(err, &rerr) {
+			fmt.Fprintln(os.Stderr, rerr.HelpText())
+			fmt.Fprintf(os.Stderr, "(%v)\n", rerr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Whoops, something went wrong: %v.\n", err)
+		}
 		os.Exit(1)
 	}
 	fmt.Println(result.Message())
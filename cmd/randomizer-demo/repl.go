@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/featherbread/randomizer/internal/markdown"
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+const replHelp = `Enter randomizer arguments as you would in a shell, like:
+  /save snacks chips pretzels "potato salad"
+  snacks
+
+REPL commands:
+  :partition         Show the current partition.
+  :partition NAME    Switch to the groups of another partition.
+  :help              Show this help.
+  :quit              Exit (as do Ctrl-C and Ctrl-D).
+`
+
+// repl runs the randomizer interactively, one line of input at a time.
+type repl struct {
+	name         string
+	storeFactory func(partition string) randomizer.Store
+	options      []randomizer.Option
+	partition    string
+	out          io.Writer
+}
+
+// lineReader reads lines of input for the REPL.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// prompter is implemented by lineReaders that can show a prompt for input.
+type prompter interface {
+	SetPrompt(prompt string)
+}
+
+// runInteractive runs the REPL on standard input and output. When standard
+// input is a terminal, the REPL supports line editing and history through the
+// terminal's raw mode. Otherwise, it simply reads lines without a prompt, so
+// that it can run scripts from pipes and files.
+func (r *repl) runInteractive(ctx context.Context) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		r.out = os.Stdout
+		return r.serve(ctx, scannerReader{bufio.NewScanner(os.Stdin)})
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "")
+	if width, height, err := term.GetSize(fd); err == nil {
+		terminal.SetSize(width, height)
+	}
+	r.out = terminal
+	fmt.Fprintln(r.out, "Type :help for help, or :quit to exit.")
+	return r.serve(ctx, terminal)
+}
+
+// serve reads and handles lines of input until the input ends or the user
+// quits.
+func (r *repl) serve(ctx context.Context, input lineReader) error {
+	for {
+		if p, ok := input.(prompter); ok {
+			p.SetPrompt(r.partition + "> ")
+		}
+		line, err := input.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if quit := r.handle(ctx, line); quit {
+			return nil
+		}
+	}
+}
+
+// handle handles a single line of input, and reports whether the user asked
+// to quit.
+func (r *repl) handle(ctx context.Context, line string) (quit bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	// REPL commands start with an unquoted colon, so quoting lets a colon
+	// through to the randomizer.
+	isCommand := strings.HasPrefix(line, ":")
+	args, err := splitWords(strings.TrimPrefix(line, ":"))
+	if err != nil {
+		fmt.Fprintf(r.out, "Whoops, I couldn't read that line: %v.\n", err)
+		return false
+	}
+
+	if isCommand {
+		return r.command(args)
+	}
+
+	app := randomizer.NewApp(r.name, r.storeFactory(r.partition), r.options...)
+	result, err := app.Main(ctx, args)
+	if err != nil {
+		var rerr randomizer.Error
+		if errors.As(err, &rerr) {
+			fmt.Fprintln(r.out, markdown.PlainText(rerr.HelpText()))
+		} else {
+			fmt.Fprintf(r.out, "Whoops, something went wrong: %v.\n", err)
+		}
+		return false
+	}
+	fmt.Fprintln(r.out, markdown.PlainText(result.Message()))
+	return false
+}
+
+// command handles a REPL command, given its name and arguments without the
+// leading colon.
+func (r *repl) command(args []string) (quit bool) {
+	if len(args) == 0 {
+		fmt.Fprintln(r.out, "Whoops, that command is missing a name. Type :help for help.")
+		return false
+	}
+
+	switch args[0] {
+	case "partition":
+		switch len(args) {
+		case 1:
+			fmt.Fprintf(r.out, "The current partition is %q.\n", r.partition)
+		case 2:
+			if args[1] == "" {
+				fmt.Fprintln(r.out, "Whoops, the partition name can't be empty.")
+				return false
+			}
+			r.partition = args[1]
+			fmt.Fprintf(r.out, "Switched to the %q partition.\n", r.partition)
+		default:
+			fmt.Fprintln(r.out, "Whoops, :partition takes a single name. Quote it if it has spaces.")
+		}
+	case "help":
+		fmt.Fprint(r.out, replHelp)
+	case "quit", "exit":
+		return true
+	default:
+		fmt.Fprintf(r.out, "Whoops, I don't know the :%s command. Type :help for help.\n", args[0])
+	}
+	return false
+}
+
+// scannerReader adapts a bufio.Scanner to a lineReader.
+type scannerReader struct {
+	*bufio.Scanner
+}
+
+func (s scannerReader) ReadLine() (string, error) {
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return s.Text(), nil
+}
+
+// splitWords splits a line into words like a POSIX shell, without any
+// expansions. Whitespace separates words, single quotes preserve everything
+// within them, double quotes preserve everything but backslash escapes of
+// '"' and '\', and a backslash outside of quotes preserves the next character.
+func splitWords(line string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+		quote  rune
+		escape bool
+	)
+	for _, c := range line {
+		switch {
+		case escape:
+			if quote == '"' && c != '"' && c != '\\' {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(c)
+			escape = false
+
+		case c == '\\' && quote != '\'':
+			escape = true
+			inWord = true
+
+		case quote != 0 && c == quote:
+			quote = 0
+
+		case quote != 0:
+			word.WriteRune(c)
+
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	switch {
+	case escape:
+		return nil, errors.New("the line ends with a backslash")
+	case quote != 0:
+		return nil, fmt.Errorf("a %c quote is missing its end", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "", want: nil},
+		{line: "  one\ttwo  ", want: []string{"one", "two"}},
+		{line: `/save snacks "potato salad" 'fish & chips'`, want: []string{"/save", "snacks", "potato salad", "fish & chips"}},
+		{line: `a""b '' ""`, want: []string{"ab", "", ""}},
+		{line: `don\'t "say \"hi\" \n" 'no \escapes'`, want: []string{"don't", `say "hi" \n`, `no \escapes`}},
+		{line: `one\ two`, want: []string{"one two"}},
+		{line: `"unterminated`, wantErr: true},
+		{line: `trailing\`, wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := splitWords(tc.line)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("splitWords(%q) returned error %v, want error %v", tc.line, err, tc.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestREPL(t *testing.T) {
+	stores := new(rndtest.Partitions)
+	var out strings.Builder
+	r := &repl{
+		name:         "randomizer",
+		storeFactory: func(partition string) randomizer.Store { return stores.Store(partition) },
+		partition:    "Groups",
+		out:          &out,
+	}
+
+	input := strings.Join([]string{
+		`/save snacks chips "potato salad"`,
+		`:partition ""`,
+		`:partition "Other Channel"`,
+		`/save drinks tea coffee`,
+		`:partition`,
+		`/show "potato salad"`,
+		`"unterminated`,
+		`:bogus`,
+		`:quit`,
+		`/delete drinks`,
+	}, "\n")
+	if err := r.serve(context.Background(), scannerReader{bufio.NewScanner(strings.NewReader(input))}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := stores.Stores()[""]; ok {
+		t.Error("REPL made a store for an empty partition")
+	}
+	if got := stores.Store("Groups").Store["snacks"]; !slices.Equal(got, []string{"chips", "potato salad"}) {
+		t.Errorf("unexpected snacks group %q", got)
+	}
+	if got := stores.Store("Other Channel").Store["drinks"]; !slices.Equal(got, []string{"coffee", "tea"}) {
+		t.Errorf("unexpected drinks group %q (the REPL may have ignored :quit)", got)
+	}
+
+	wantLines := []string{
+		`Done! The "snacks" group was saved in this channel with the following options:`,
+		`Whoops, the partition name can't be empty.`,
+		`Switched to the "Other Channel" partition.`,
+		`The current partition is "Other Channel".`,
+		`Whoops, I couldn't read that line: a " quote is missing its end.`,
+		`Whoops, I don't know the :bogus command. Type :help for help.`,
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "create it!)\nWhoops, I couldn't read") {
+		t.Errorf("output shows more than help text for a randomizer error:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "*") {
+		t.Errorf("output contains mrkdwn formatting:\n%s", out.String())
+	}
+}
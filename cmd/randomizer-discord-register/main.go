@@ -0,0 +1,43 @@
+// The randomizer-discord-register command registers the randomizer's
+// application command with Discord, so that users can invoke it through the
+// interactions endpoint served by randomizer-server or randomizer-lambda.
+//
+// It reads the application's ID from DISCORD_APPLICATION_ID, and its bot token
+// from DISCORD_BOT_TOKEN. Registering the command replaces any other commands
+// that the application has.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/featherbread/randomizer/internal/discord"
+)
+
+var (
+	flagName  = flag.String("name", "randomize", "name of the command to register")
+	flagGuild = flag.String("guild", "", "register the command in this guild alone, rather than globally")
+)
+
+func main() {
+	flag.Parse()
+
+	client, err := discord.ClientFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to configure Discord client: %v\n", err)
+		os.Exit(2)
+	}
+
+	if err := client.RegisterCommands(context.Background(), *flagGuild, discord.Commands(*flagName)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to register commands: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *flagGuild != "" {
+		fmt.Printf("Registered /%s in guild %s.\n", *flagName, *flagGuild)
+	} else {
+		fmt.Printf("Registered /%s globally. It may take some time to appear in every guild.\n", *flagName)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// backgroundTasks runs work that continues after the handler returns a
+// response, like the deferred results of slow slash commands.
+//
+// Lambda normally freezes the execution environment as soon as the handler
+// returns, which would suspend this work until the next invocation (if there is
+// one). To prevent this, backgroundTasks registers itself as an [internal
+// extension] of the function. Lambda sends the response to the client as soon
+// as the handler returns, but doesn't freeze the environment until every
+// extension is ready for the next invocation, which backgroundTasks holds off
+// until its pending work is done.
+//
+// [internal extension]: https://docs.aws.amazon.com/lambda/latest/dg/lambda-extensions.html
+type backgroundTasks struct {
+	client      *http.Client
+	baseURL     string
+	extensionID string
+
+	pending  sync.WaitGroup
+	returned chan struct{}
+}
+
+// extensionName identifies the randomizer's internal extension to Lambda.
+const extensionName = "randomizer-background-tasks"
+
+// registerBackgroundTasks registers a new backgroundTasks extension with the
+// Lambda Extensions API, and starts processing its events. It must be called
+// before the Lambda runtime starts handling invocations.
+func registerBackgroundTasks() (*backgroundTasks, error) {
+	runtimeAPI, ok := os.LookupEnv("AWS_LAMBDA_RUNTIME_API")
+	if !ok {
+		return nil, errors.New("AWS_LAMBDA_RUNTIME_API is not set")
+	}
+
+	b := &backgroundTasks{
+		client:   &http.Client{},
+		baseURL:  "http://" + runtimeAPI + "/2020-01-01/extension",
+		returned: make(chan struct{}, 1),
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.baseURL+"/register", strings.NewReader(`{"events":["INVOKE"]}`))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Lambda-Extension-Name", extensionName)
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registering extension: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registering extension: HTTP status %s", resp.Status)
+	}
+
+	b.extensionID = resp.Header.Get("Lambda-Extension-Identifier")
+	go b.processEvents()
+	return b, nil
+}
+
+// Run runs task in a new goroutine, and keeps the execution environment running
+// until it finishes. It must be called while the handler is running.
+func (b *backgroundTasks) Run(task func()) {
+	b.pending.Add(1)
+	go func() {
+		defer b.pending.Done()
+		task()
+	}()
+}
+
+// Returned must be called as each invocation of the handler returns.
+func (b *backgroundTasks) Returned() {
+	b.returned <- struct{}{}
+}
+
+// processEvents waits for each invocation, then for its handler to return and
+// its background tasks to finish, before telling Lambda that the extension is
+// ready for the next invocation.
+func (b *backgroundTasks) processEvents() {
+	for {
+		if err := b.nextEvent(); err != nil {
+			// There's no way to recover from this. Lambda will fail the
+			// invocation and reset the execution environment.
+			panic(fmt.Errorf("waiting for next Lambda extension event: %w", err))
+		}
+		<-b.returned
+		b.pending.Wait()
+	}
+}
+
+func (b *backgroundTasks) nextEvent() error {
+	req, err := http.NewRequest(http.MethodGet, b.baseURL+"/event/next", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Lambda-Extension-Identifier", b.extensionID)
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP status %s", resp.Status)
+	}
+	return nil
+}
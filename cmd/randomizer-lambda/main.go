@@ -1,5 +1,6 @@
 // The randomizer-lambda command is an AWS Lambda handler that serves the Slack
-// slash command API for the randomizer.
+// slash command API for the randomizer, and optionally the Discord
+// interactions API at the /discord path.
 //
 // The handler expects HTTP request events using the [Amazon API Gateway
 // payload format version 2.0]. This makes it suitable for invocation through a
@@ -16,11 +17,15 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"os"
 
+	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/awslabs/aws-lambda-go-api-proxy/httpadapter"
 
+	"github.com/featherbread/randomizer/internal/discord"
+	"github.com/featherbread/randomizer/internal/randomizer"
 	"github.com/featherbread/randomizer/internal/slack"
 	"github.com/featherbread/randomizer/internal/store/dynamodb"
 )
@@ -34,16 +39,122 @@ func main() {
 		os.Exit(2)
 	}
 
+	signingSecretProvider, err := slack.SigningSecretProviderFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack signing secret", "err", err)
+		os.Exit(2)
+	}
+
+	discordPublicKey, err := discord.PublicKeyFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Discord public key", "err", err)
+		os.Exit(2)
+	}
+
+	slackEnabled := tokenProvider != nil || signingSecretProvider != nil
+	if !slackEnabled && discordPublicKey == nil {
+		logger.Error("Missing Slack token or signing secret, or Discord public key, in environment")
+		os.Exit(2)
+	}
+
+	verificationMode, err := slack.VerificationModeFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack verification mode", "err", err)
+		os.Exit(2)
+	}
+
+	deferAfter, err := slack.DeferAfterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure deferred responses", "err", err)
+		os.Exit(2)
+	}
+
+	rateLimiter, err := slack.RateLimiterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure rate limits", "err", err)
+		os.Exit(2)
+	}
+
+	background, err := registerBackgroundTasks()
+	if err != nil {
+		logger.Error("Failed to set up background tasks; disabling deferred responses", "err", err)
+		deferAfter = 0
+	}
+
+	rules, err := randomizer.RulesFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure group rules", "err", err)
+		os.Exit(2)
+	}
+
 	storeFactory, err := dynamodb.FactoryFromEnv(context.Background())
 	if err != nil {
 		logger.Error("Failed to create DynamoDB store", "err", err)
 		os.Exit(2)
 	}
 
+	oauth, err := slack.OAuthConfigFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack OAuth", "err", err)
+		os.Exit(2)
+	}
+
+	client, err := slack.ClientFromEnv(storeFactory)
+	if err != nil {
+		logger.Error("Failed to configure Slack Web API client", "err", err)
+		os.Exit(2)
+	}
+
 	app := slack.App{
-		TokenProvider: tokenProvider,
-		StoreFactory:  storeFactory,
-		Logger:        logger,
+		TokenProvider:         tokenProvider,
+		SigningSecretProvider: signingSecretProvider,
+		VerificationMode:      verificationMode,
+		StoreFactory:          storeFactory,
+		Rules:                 rules,
+		Client:                client,
+		OAuth:                 oauth,
+		DeferAfter:            deferAfter,
+		RateLimiter:           rateLimiter,
+		RetryCache:            &slack.RetryCache{},
+		HomeCache:             &slack.HomeCache{},
+		Logger:                logger,
+	}
+	if background != nil {
+		app.RunInBackground = background.Run
+	} else {
+		// Without background tasks, work that would otherwise continue after
+		// the handler returns could be frozen indefinitely.
+		app.RunInBackground = func(task func()) { task() }
+	}
+
+	mux := http.NewServeMux()
+	if slackEnabled {
+		mux.Handle("/", app)
+		mux.Handle("/interactions", app.InteractionHandler())
+		if client != nil {
+			mux.Handle("/events", app.EventHandler())
+		}
+		if oauth != nil {
+			mux.Handle("GET /install", app.InstallHandler())
+			mux.Handle("GET /oauth/redirect", app.OAuthRedirectHandler())
+		}
+	}
+	if discordPublicKey != nil {
+		mux.Handle("/discord", discord.App{
+			PublicKey:    discordPublicKey,
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Logger:       logger,
+		})
+	}
+	adapter := httpadapter.NewV2(mux)
+
+	if background == nil {
+		lambda.Start(adapter.ProxyWithContext)
+		return
 	}
-	lambda.Start(httpadapter.NewV2(app).ProxyWithContext)
+	lambda.Start(func(ctx context.Context, event events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		defer background.Returned()
+		return adapter.ProxyWithContext(ctx, event)
+	})
 }
@@ -1,5 +1,11 @@
 // The randomizer-server command is an HTTP server that serves the Slack slash
-// command API for the randomizer.
+// command API for the randomizer, and optionally the Discord interactions API
+// at the /discord path, a Microsoft Teams outgoing webhook at the /teams path,
+// a Mattermost slash command at the /mattermost path, a JSON REST API under
+// the /v1/ path, and a web interface for managing groups under the /ui/ path.
+// With the -grpc-addr flag, it also serves the randomizer as a
+// gRPC service on a separate listener, using TLS with the -grpc-tls-cert and
+// -grpc-tls-key flags.
 //
 // See the randomizer repository README for more information on configuring and
 // deploying the server.
@@ -9,19 +15,35 @@ import (
 	"context"
 	"flag"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/featherbread/randomizer/internal/apikey"
+	"github.com/featherbread/randomizer/internal/discord"
+	"github.com/featherbread/randomizer/internal/grpcapi"
+	"github.com/featherbread/randomizer/internal/httpapi"
+	"github.com/featherbread/randomizer/internal/mattermost"
+	"github.com/featherbread/randomizer/internal/randomizer"
 	"github.com/featherbread/randomizer/internal/slack"
 	"github.com/featherbread/randomizer/internal/store"
+	"github.com/featherbread/randomizer/internal/teams"
+	"github.com/featherbread/randomizer/internal/webui"
 )
 
 var exitSignals = []os.Signal{os.Interrupt}
 
 var (
-	flagAddr    = flag.String("addr", ":7636", "address to bind the server to")
-	flagLogJSON = flag.Bool("log-json", false, "log JSON to stderr instead of text")
+	flagAddr     = flag.String("addr", ":7636", "address to bind the server to")
+	flagGRPCAddr = flag.String("grpc-addr", "", "address to bind the gRPC server to, if any (expose it only over TLS)")
+	flagGRPCCert = flag.String("grpc-tls-cert", "", "PEM certificate file for the gRPC server to serve TLS with")
+	flagGRPCKey  = flag.String("grpc-tls-key", "", "PEM private key file for the gRPC server to serve TLS with")
+	flagLogJSON  = flag.Bool("log-json", false, "log JSON to stderr instead of text")
 )
 
 func main() {
@@ -38,30 +60,218 @@ func main() {
 		os.Exit(2)
 	}
 
+	signingSecretProvider, err := slack.SigningSecretProviderFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack signing secret", "err", err)
+		os.Exit(2)
+	}
+
+	discordPublicKey, err := discord.PublicKeyFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Discord public key", "err", err)
+		os.Exit(2)
+	}
+
+	teamsSecurityToken, err := teams.SecurityTokenFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Teams security token", "err", err)
+		os.Exit(2)
+	}
+
+	mattermostTokenProvider, err := mattermost.TokenProviderFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Mattermost token", "err", err)
+		os.Exit(2)
+	}
+
+	apiKeys, err := apikey.FromEnv()
+	if err != nil {
+		logger.Error("Failed to configure API keys", "err", err)
+		os.Exit(2)
+	}
+
+	uiAuth, err := webui.AuthFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure web interface", "err", err)
+		os.Exit(2)
+	}
+
+	slackEnabled := tokenProvider != nil || signingSecretProvider != nil
+	if !slackEnabled && discordPublicKey == nil && teamsSecurityToken == nil && mattermostTokenProvider == nil && apiKeys == nil && !uiAuth.Enabled() {
+		logger.Error("Missing Slack token or signing secret, Discord public key, Teams security token, Mattermost token, API keys, or web interface credentials in environment")
+		os.Exit(2)
+	}
+	if *flagGRPCAddr != "" && apiKeys == nil {
+		logger.Error("Missing API keys for the gRPC server in environment")
+		os.Exit(2)
+	}
+	if (*flagGRPCCert == "") != (*flagGRPCKey == "") {
+		logger.Error("The -grpc-tls-cert and -grpc-tls-key flags must be set together")
+		os.Exit(2)
+	}
+
+	verificationMode, err := slack.VerificationModeFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack verification mode", "err", err)
+		os.Exit(2)
+	}
+
+	deferAfter, err := slack.DeferAfterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure deferred responses", "err", err)
+		os.Exit(2)
+	}
+
+	rateLimiter, err := slack.RateLimiterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure rate limits", "err", err)
+		os.Exit(2)
+	}
+
+	rules, err := randomizer.RulesFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure group rules", "err", err)
+		os.Exit(2)
+	}
+
 	storeFactory, err := store.FactoryFromEnv(context.Background())
 	if err != nil {
 		logger.Error("Failed to create store", "err", err)
 		os.Exit(2)
 	}
 
+	oauth, err := slack.OAuthConfigFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack OAuth", "err", err)
+		os.Exit(2)
+	}
+
+	client, err := slack.ClientFromEnv(storeFactory)
+	if err != nil {
+		logger.Error("Failed to configure Slack Web API client", "err", err)
+		os.Exit(2)
+	}
+
+	// Deferred responses finish in the background, and should still be posted
+	// to Slack if the server shuts down before they're done.
+	var background sync.WaitGroup
+	app := slack.App{
+		TokenProvider:         tokenProvider,
+		SigningSecretProvider: signingSecretProvider,
+		VerificationMode:      verificationMode,
+		StoreFactory:          storeFactory,
+		Rules:                 rules,
+		Client:                client,
+		OAuth:                 oauth,
+		DeferAfter:            deferAfter,
+		RateLimiter:           rateLimiter,
+		RetryCache:            &slack.RetryCache{},
+		HomeCache:             &slack.HomeCache{},
+		RunInBackground: func(task func()) {
+			background.Add(1)
+			go func() {
+				defer background.Done()
+				task()
+			}()
+		},
+		Logger: logger,
+	}
+
 	mux := http.NewServeMux()
-	mux.Handle("/", slack.App{
-		TokenProvider: tokenProvider,
-		StoreFactory:  storeFactory,
-		Logger:        logger,
-	})
+	if slackEnabled {
+		mux.Handle("/", app)
+		mux.Handle("/interactions", app.InteractionHandler())
+		if client != nil {
+			mux.Handle("/events", app.EventHandler())
+		}
+		if oauth != nil {
+			mux.Handle("GET /install", app.InstallHandler())
+			mux.Handle("GET /oauth/redirect", app.OAuthRedirectHandler())
+		}
+	}
+	if discordPublicKey != nil {
+		mux.Handle("/discord", discord.App{
+			PublicKey:    discordPublicKey,
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Logger:       logger,
+		})
+	}
+	if teamsSecurityToken != nil {
+		mux.Handle("/teams", teams.App{
+			SecurityToken: teamsSecurityToken,
+			StoreFactory:  storeFactory,
+			Rules:         rules,
+			Logger:        logger,
+		})
+	}
+	if mattermostTokenProvider != nil {
+		mux.Handle("/mattermost", mattermost.App{
+			TokenProvider: mattermostTokenProvider,
+			StoreFactory:  storeFactory,
+			Rules:         rules,
+			Logger:        logger,
+		})
+	}
+	if apiKeys != nil {
+		mux.Handle("/v1/", httpapi.App{
+			APIKeys:      apiKeys,
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Logger:       logger,
+		}.Handler())
+	}
+	if uiAuth.Enabled() {
+		mux.Handle("/ui/", http.StripPrefix("/ui", webui.App{
+			Auth:         uiAuth,
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Logger:       logger,
+		}.Handler()))
+	}
 	mux.Handle("GET /healthz",
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
 		}))
 
 	srv := &http.Server{Addr: *flagAddr, Handler: mux}
-	srvErr := make(chan error, 1)
+	srvErr := make(chan error, 2)
 	go func() {
 		logger.Info("Starting randomizer server", "addr", *flagAddr)
 		srvErr <- srv.ListenAndServe()
 	}()
 
+	var grpcSrv *grpc.Server
+	if *flagGRPCAddr != "" {
+		var opts []grpc.ServerOption
+		if *flagGRPCCert != "" {
+			creds, err := credentials.NewServerTLSFromFile(*flagGRPCCert, *flagGRPCKey)
+			if err != nil {
+				logger.Error("Failed to load gRPC TLS certificate", "err", err)
+				os.Exit(2)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		} else {
+			logger.Warn("Serving gRPC without TLS; expose it only behind a TLS proxy")
+		}
+
+		lis, err := net.Listen("tcp", *flagGRPCAddr)
+		if err != nil {
+			logger.Error("Failed to start gRPC server", "err", err)
+			os.Exit(1)
+		}
+		grpcSrv = (&grpcapi.Server{
+			APIKeys:      apiKeys,
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Logger:       logger,
+		}).NewGRPCServer(opts...)
+		go func() {
+			logger.Info("Starting gRPC server", "addr", *flagGRPCAddr, "tls", *flagGRPCCert != "")
+			srvErr <- grpcSrv.Serve(lis)
+		}()
+	}
+
 	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, exitSignals...)
 
@@ -79,4 +289,8 @@ func main() {
 	if err != nil {
 		logger.Error("Failed to shut down gracefully", "err", err)
 	}
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
+	background.Wait()
 }
@@ -0,0 +1,112 @@
+// The randomizer-socket command serves the randomizer to Slack over a Socket
+// Mode connection, for deployments that can't accept HTTP requests from Slack.
+//
+// See the randomizer repository README for more information on configuring and
+// deploying the randomizer.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/slack"
+	"github.com/featherbread/randomizer/internal/store"
+)
+
+var exitSignals = []os.Signal{os.Interrupt}
+
+var flagLogJSON = flag.Bool("log-json", false, "log JSON to stderr instead of text")
+
+func main() {
+	flag.Parse()
+
+	logger := slog.Default()
+	if *flagLogJSON {
+		logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	}
+
+	appTokenProvider, err := slack.AppTokenProviderFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure Slack app token", "err", err)
+		os.Exit(2)
+	}
+
+	deferAfter, err := slack.DeferAfterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure deferred responses", "err", err)
+		os.Exit(2)
+	}
+
+	rateLimiter, err := slack.RateLimiterFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure rate limits", "err", err)
+		os.Exit(2)
+	}
+
+	rules, err := randomizer.RulesFromEnv()
+	if err != nil {
+		logger.Error("Failed to configure group rules", "err", err)
+		os.Exit(2)
+	}
+
+	// Slack doesn't allow apps that use Socket Mode to be distributed to other
+	// workspaces.
+	if _, ok := os.LookupEnv("SLACK_CLIENT_ID"); ok {
+		logger.Error("Slack OAuth is not supported with Socket Mode")
+		os.Exit(2)
+	}
+
+	storeFactory, err := store.FactoryFromEnv(context.Background())
+	if err != nil {
+		logger.Error("Failed to create store", "err", err)
+		os.Exit(2)
+	}
+
+	client, err := slack.ClientFromEnv(storeFactory)
+	if err != nil {
+		logger.Error("Failed to configure Slack Web API client", "err", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), exitSignals...)
+	defer stop()
+
+	// Deferred responses finish in the background, and should still be posted
+	// to Slack if the connection shuts down before they're done.
+	var background sync.WaitGroup
+	socket := slack.SocketMode{
+		App: slack.App{
+			StoreFactory: storeFactory,
+			Rules:        rules,
+			Client:       client,
+			DeferAfter:   deferAfter,
+			RateLimiter:  rateLimiter,
+			RetryCache:   &slack.RetryCache{},
+			HomeCache:    &slack.HomeCache{},
+			RunInBackground: func(task func()) {
+				background.Add(1)
+				go func() {
+					defer background.Done()
+					task()
+				}()
+			},
+			Logger: logger,
+		},
+		AppTokenProvider: appTokenProvider,
+	}
+
+	logger.Info("Starting randomizer over Socket Mode")
+	if err := socket.Run(ctx); err != nil {
+		logger.Error("Failed to serve Socket Mode connection", "err", err)
+		os.Exit(1)
+	}
+
+	stop()
+	logger.Info("Shutting down; interrupt again to force exit")
+	background.Wait()
+}
@@ -0,0 +1,57 @@
+// Package apikey manages the keys that grant access to the randomizer's REST
+// and gRPC APIs.
+package apikey
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Key is a secret that grants access to the APIs.
+type Key struct {
+	// Name identifies the holder of the key, and is recorded as the creator of
+	// the groups that it saves.
+	Name string
+	// Secret is the value of the key, which clients present as a bearer token.
+	Secret string
+}
+
+// FromEnv returns the API keys in RANDOMIZER_API_KEYS, a comma-separated list
+// of NAME:KEY pairs. If it is unset, FromEnv returns nil keys and a nil error,
+// as the APIs are optional.
+func FromEnv() ([]Key, error) {
+	env, ok := os.LookupEnv("RANDOMIZER_API_KEYS")
+	if !ok {
+		return nil, nil
+	}
+
+	var keys []Key
+	for entry := range strings.SplitSeq(env, ",") {
+		name, secret, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || name == "" || secret == "" {
+			return nil, fmt.Errorf("RANDOMIZER_API_KEYS entry %q is not of the form NAME:KEY", entry)
+		}
+		keys = append(keys, Key{Name: name, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Match returns the key in keys whose secret value is token, if any.
+func Match(keys []Key, token string) (key Key, ok bool) {
+	if token == "" {
+		return Key{}, false
+	}
+
+	// Compare against every key, so that the time taken doesn't reveal which one
+	// matched.
+	subtle.WithDataIndependentTiming(func() {
+		for _, candidate := range keys {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate.Secret)) == 1 {
+				key, ok = candidate, true
+			}
+		}
+	})
+	return
+}
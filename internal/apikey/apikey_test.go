@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("RANDOMIZER_API_KEYS", "ci:one, tools:two:three")
+	keys, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Key{{Name: "ci", Secret: "one"}, {Name: "tools", Secret: "two:three"}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %v, want %v", keys, want)
+	}
+
+	t.Setenv("RANDOMIZER_API_KEYS", "nokey")
+	if _, err := FromEnv(); err == nil {
+		t.Error("unexpected success with invalid keys")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	keys := []Key{{Name: "ci", Secret: "one"}, {Name: "tools", Secret: "two"}}
+	if key, ok := Match(keys, "two"); !ok || key.Name != "tools" {
+		t.Errorf("Match(keys, %q) = %v, %v; want tools key", "two", key, ok)
+	}
+	for _, token := range []string{"", "three"} {
+		if key, ok := Match(keys, token); ok {
+			t.Errorf("Match(keys, %q) unexpectedly matched %v", token, key)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// DefaultBaseURL is the base URL of the Discord HTTP API.
+const DefaultBaseURL = "https://discord.com/api/v10/"
+
+// inputOptionName is the name of the option that holds the randomizer's
+// arguments, like the text of a Slack slash command.
+const inputOptionName = "input"
+
+// Command and option types from the Discord API.
+const (
+	commandTypeChatInput = 1
+	optionTypeString     = 3
+)
+
+// Command represents a Discord application command.
+type Command struct {
+	Name        string          `json:"name"`
+	Type        int             `json:"type"`
+	Description string          `json:"description"`
+	Options     []CommandOption `json:"options,omitempty"`
+}
+
+// CommandOption represents an option of a Discord application command.
+type CommandOption struct {
+	Name        string `json:"name"`
+	Type        int    `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// Commands returns the application commands that App serves: a single command
+// with the provided name, which takes the randomizer's arguments in one text
+// option, as in "/randomize input: /save snacks chips pretzels".
+func Commands(name string) []Command {
+	return []Command{{
+		Name:        name,
+		Type:        commandTypeChatInput,
+		Description: "Randomize the order of options in a list",
+		Options: []CommandOption{{
+			Name:        inputOptionName,
+			Type:        optionTypeString,
+			Description: "Options to randomize, a saved group, or a flag like /save (leave empty for help)",
+		}},
+	}}
+}
+
+// Client calls the parts of the Discord HTTP API that manage the randomizer's
+// application commands.
+type Client struct {
+	// ApplicationID is the ID of the Discord application.
+	ApplicationID string
+	// BotToken authenticates requests as the application's bot user.
+	BotToken string
+	// BaseURL, if non-empty, overrides DefaultBaseURL. It must end with a slash.
+	BaseURL string
+	// HTTPClient, if non-nil, overrides http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// ClientFromEnv returns a Client for the application whose ID is in
+// DISCORD_APPLICATION_ID, authenticated by the bot token in DISCORD_BOT_TOKEN.
+func ClientFromEnv() (Client, error) {
+	client := Client{
+		ApplicationID: os.Getenv("DISCORD_APPLICATION_ID"),
+		BotToken:      os.Getenv("DISCORD_BOT_TOKEN"),
+	}
+	if client.ApplicationID == "" || client.BotToken == "" {
+		return Client{}, errors.New("missing DISCORD_APPLICATION_ID or DISCORD_BOT_TOKEN in environment")
+	}
+	return client, nil
+}
+
+// RegisterCommands replaces all of the application's commands with the
+// provided ones. If guildID is non-empty, it registers the commands in that
+// guild alone, where they're available right away; otherwise, it registers
+// them globally, which can take some time to reach every guild.
+func (c Client) RegisterCommands(ctx context.Context, guildID string, commands []Command) error {
+	path := "applications/" + url.PathEscape(c.ApplicationID)
+	if guildID != "" {
+		path += "/guilds/" + url.PathEscape(guildID)
+	}
+	path += "/commands"
+
+	body, err := json.Marshal(commands)
+	if err != nil {
+		return fmt.Errorf("encoding commands: %w", err)
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bot "+c.BotToken)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("registering commands: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("registering commands: HTTP status %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
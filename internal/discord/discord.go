@@ -0,0 +1,261 @@
+// Package discord supports invoking the randomizer as a Discord application
+// command.
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/featherbread/randomizer/internal/markdown"
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+// SignatureMaxAge is the maximum difference between the current time and the
+// timestamp of a signed request. App rejects signed requests outside of this
+// window to protect against replay attacks.
+const SignatureMaxAge = 5 * time.Minute
+
+// App serves the randomizer through the Discord interactions API, as the
+// application command described by [Commands].
+//
+// App confirms the legitimacy of requests from Discord by checking their
+// Ed25519 signatures with the application's public key.
+type App struct {
+	// PublicKey is the key that Discord signs requests with. This can be
+	// obtained from the "General Information" page of the application.
+	PublicKey ed25519.PublicKey
+	// StoreFactory provides a Store for the Discord channel in which the
+	// request was made. Partitions are named by the IDs of the guild (if any)
+	// and the channel, separated by colons and prefixed with "discord:" to keep
+	// them apart from those of other frontends.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// PublicKeyFromEnv returns the public key in DISCORD_PUBLIC_KEY, which must be
+// hex-encoded as shown in the application's configuration. If it is unset,
+// PublicKeyFromEnv returns a nil key and a nil error, as Discord support is
+// optional.
+func PublicKeyFromEnv() (ed25519.PublicKey, error) {
+	env, ok := os.LookupEnv("DISCORD_PUBLIC_KEY")
+	if !ok {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(env)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("DISCORD_PUBLIC_KEY is not a hex-encoded Ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Interaction and response types from the Discord API.
+const (
+	interactionPing               = 1
+	interactionApplicationCommand = 2
+
+	responsePong                     = 1
+	responseChannelMessageWithSource = 4
+
+	messageFlagEphemeral = 1 << 6
+)
+
+// maxContentLength is Discord's limit on the length of a message's content.
+const maxContentLength = 2000
+
+// interaction represents the body of a request from the Discord interactions
+// API. It includes only the fields that the randomizer uses.
+type interaction struct {
+	Type      int    `json:"type"`
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	Member    *struct {
+		User user `json:"user"`
+	} `json:"member"`
+	User *user `json:"user"`
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string `json:"name"`
+			Value any    `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+type user struct {
+	ID string `json:"id"`
+}
+
+// userID returns the ID of the user who invoked the interaction, which Discord
+// provides in different places for guilds and DMs.
+func (i interaction) userID() string {
+	if i.Member != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// input returns the value of the command's input option.
+func (i interaction) input() string {
+	for _, option := range i.Data.Options {
+		if option.Name == inputOptionName {
+			if value, ok := option.Value.(string); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+type response struct {
+	Type int           `json:"type"`
+	Data *responseData `json:"data,omitempty"`
+}
+
+type responseData struct {
+	Content         string          `json:"content"`
+	Flags           int             `json:"flags,omitempty"`
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+// allowedMentions limits the mentions in a message that notify anyone. The
+// randomizer notifies users that it selects, but never whole roles or
+// @everyone.
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// ServeHTTP serves POST requests from Discord.
+func (a App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Add("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhook.MaxBodySize))
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to read request body")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Discord requires a 401 response to requests with invalid signatures, and
+	// periodically sends such requests to check.
+	if !a.isSignatureValid(r.Header, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var i interaction
+	if err := json.Unmarshal(body, &i); err != nil {
+		webhook.LogError(a.Logger, err, "Failed to decode interaction")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch i.Type {
+	case interactionPing:
+		webhook.WriteJSON(w, a.Logger, response{Type: responsePong})
+	case interactionApplicationCommand:
+		webhook.WriteJSON(w, a.Logger, a.handleCommand(r.Context(), i))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// isSignatureValid checks the X-Signature-Ed25519 header of a request, which
+// Discord computes over the X-Signature-Timestamp header and the raw request
+// body.
+func (a App) isSignatureValid(header http.Header, body []byte) bool {
+	timestamp := header.Get("X-Signature-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > SignatureMaxAge || age < -SignatureMaxAge {
+		return false
+	}
+
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(a.PublicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	message := append([]byte(timestamp), body...)
+	return ed25519.Verify(a.PublicKey, message, signature)
+}
+
+// handleCommand runs the randomizer for a verified application command, and
+// returns the response to show to the user.
+func (a App) handleCommand(ctx context.Context, i interaction) response {
+	options := []randomizer.Option{randomizer.WithRules(a.Rules)}
+	if userID := i.userID(); userID != "" {
+		options = append(options, randomizer.WithUser(mention(userID)))
+	}
+
+	partition := joinPartition("discord", i.GuildID, i.ChannelID)
+	app := randomizer.NewApp("/"+i.Data.Name, a.StoreFactory(partition), options...)
+	result, err := app.Main(ctx, strings.Fields(i.input()))
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to run randomizer")
+		return messageResponse(webhook.HelpText(err), true)
+	}
+	return messageResponse(result.Message(), !result.Type().IsPublic())
+}
+
+func messageResponse(message string, ephemeral bool) response {
+	data := &responseData{
+		Content:         renderMessage(message),
+		AllowedMentions: allowedMentions{Parse: []string{"users"}},
+	}
+	if ephemeral {
+		data.Flags = messageFlagEphemeral
+	}
+	return response{Type: responseChannelMessageWithSource, Data: data}
+}
+
+// renderMessage renders a message from the randomizer in Discord's Markdown
+// format, shortening it to fit in a Discord message if necessary.
+func renderMessage(message string) string {
+	message = markdown.FromMrkdwn(message)
+	if len(message) > maxContentLength {
+		cut := maxContentLength - len("…")
+		for cut > 0 && !utf8.RuneStart(message[cut]) {
+			cut--
+		}
+		message = message[:cut] + "…"
+	}
+	return message
+}
+
+func mention(userID string) string {
+	return "<@" + userID + ">"
+}
+
+func joinPartition(ids ...string) string {
+	var parts []string
+	for _, id := range ids {
+		if id != "" {
+			parts = append(parts, id)
+		}
+	}
+	return strings.Join(parts, ":")
+}
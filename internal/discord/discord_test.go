@@ -0,0 +1,209 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func newTestApp(t *testing.T) (App, ed25519.PrivateKey, *rndtest.Partitions) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := new(rndtest.Partitions)
+	app := App{
+		PublicKey:    publicKey,
+		StoreFactory: func(partition string) randomizer.Store { return stores.Store(partition) },
+	}
+	return app, privateKey, stores
+}
+
+func signedRequest(privateKey ed25519.PrivateKey, timestamp time.Time, body string) *http.Request {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	signature := ed25519.Sign(privateKey, []byte(ts+body))
+
+	req := httptest.NewRequest(http.MethodPost, "/discord", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Timestamp", ts)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	return req
+}
+
+func commandBody(input string) string {
+	body, _ := json.Marshal(map[string]any{
+		"type":       interactionApplicationCommand,
+		"guild_id":   "100",
+		"channel_id": "200",
+		"member":     map[string]any{"user": map[string]string{"id": "300"}},
+		"data": map[string]any{
+			"name":    "randomize",
+			"options": []map[string]any{{"name": inputOptionName, "type": optionTypeString, "value": input}},
+		},
+	})
+	return string(body)
+}
+
+func TestSignatureVerification(t *testing.T) {
+	app, privateKey, _ := newTestApp(t)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	ping := `{"type":1}`
+
+	testCases := []struct {
+		description string
+		req         *http.Request
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			description: "valid ping",
+			req:         signedRequest(privateKey, time.Now(), ping),
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"type":1}`,
+		},
+		{
+			description: "wrong key",
+			req:         signedRequest(otherKey, time.Now(), ping),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "old timestamp",
+			req:         signedRequest(privateKey, time.Now().Add(-2*SignatureMaxAge), ping),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "tampered body",
+			req: func() *http.Request {
+				req := signedRequest(privateKey, time.Now(), ping)
+				req.Body = io.NopCloser(strings.NewReader(`{"type":2}`))
+				return req
+			}(),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			description: "missing signature",
+			req:         httptest.NewRequest(http.MethodPost, "/discord", strings.NewReader(ping)),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "GET request",
+			req:         httptest.NewRequest(http.MethodGet, "/discord", nil),
+			wantStatus:  http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, tc.req)
+			if resp.Code != tc.wantStatus {
+				t.Errorf("got status %d, want %d", resp.Code, tc.wantStatus)
+			}
+			if body := strings.TrimSpace(resp.Body.String()); body != tc.wantBody {
+				t.Errorf("got body %q, want %q", body, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestCommands(t *testing.T) {
+	app, privateKey, stores := newTestApp(t)
+
+	run := func(input string) response {
+		t.Helper()
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, signedRequest(privateKey, time.Now(), commandBody(input)))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+		}
+		var body response
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if body.Type != responseChannelMessageWithSource || body.Data == nil {
+			t.Fatalf("unexpected response: %+v", body)
+		}
+		if !reflect.DeepEqual(body.Data.AllowedMentions.Parse, []string{"users"}) {
+			t.Errorf("response allows unexpected mentions: %v", body.Data.AllowedMentions)
+		}
+		return body
+	}
+
+	saved := run("/save snacks chips pretzels")
+	if saved.Data.Flags&messageFlagEphemeral != 0 || !strings.Contains(saved.Data.Content, `The "snacks" group was saved`) {
+		t.Errorf("unexpected response to /save: %+v", saved.Data)
+	}
+	if want := (rndtest.Store{"snacks": {"chips", "pretzels"}}); !reflect.DeepEqual(stores.Store("discord:100:200").Store, want) {
+		t.Errorf("unexpected store state: %v", stores.Stores())
+	}
+
+	selection := run("snacks")
+	if selection.Data.Flags&messageFlagEphemeral != 0 || !strings.Contains(selection.Data.Content, "**chips**") {
+		t.Errorf("unexpected response to selection: %+v", selection.Data)
+	}
+
+	help := run("")
+	if help.Data.Flags&messageFlagEphemeral == 0 || !strings.Contains(help.Data.Content, "/randomize randomizes") {
+		t.Errorf("unexpected response to help: %+v", help.Data)
+	}
+
+	missing := run("/delete nothing")
+	if missing.Data.Flags&messageFlagEphemeral == 0 || !strings.HasPrefix(missing.Data.Content, "Whoops") {
+		t.Errorf("unexpected response to error: %+v", missing.Data)
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"I randomized and got: *two*, *one*.", "I randomized and got: **two**, **one**."},
+		{strings.Repeat("é", 1500), strings.Repeat("é", 998) + "…"},
+	}
+	for _, tc := range testCases {
+		if got := renderMessage(tc.input); got != tc.want {
+			t.Errorf("renderMessage(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestRegisterCommands(t *testing.T) {
+	var (
+		gotPath, gotAuth string
+		gotCommands      []Command
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.Method+" "+r.URL.Path, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotCommands)
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	client := Client{ApplicationID: "app", BotToken: "token", BaseURL: srv.URL + "/api/", HTTPClient: srv.Client()}
+	if err := client.RegisterCommands(context.Background(), "guild", Commands("randomize")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "PUT /api/applications/app/guilds/guild/commands"; gotPath != want {
+		t.Errorf("got request %q, want %q", gotPath, want)
+	}
+	if gotAuth != "Bot token" {
+		t.Errorf("got authorization %q", gotAuth)
+	}
+	if len(gotCommands) != 1 || gotCommands[0].Name != "randomize" || gotCommands[0].Options[0].Name != inputOptionName {
+		t.Errorf("unexpected commands: %+v", gotCommands)
+	}
+}
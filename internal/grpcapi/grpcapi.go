@@ -0,0 +1,201 @@
+// Package grpcapi serves the randomizer as a gRPC service, for use by other
+// services that speak gRPC.
+//
+// The service is defined in randomizerpb/randomizer.proto.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/featherbread/randomizer/internal/apikey"
+	"github.com/featherbread/randomizer/internal/grpcapi/randomizerpb"
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// Server implements the Randomizer gRPC service.
+//
+// Every call must present one of the API keys as a bearer token in its
+// "authorization" metadata, just as with the REST API. Each key grants full
+// access to the groups of every partition.
+type Server struct {
+	randomizerpb.UnimplementedRandomizerServer
+
+	// APIKeys lists the keys that grant access to the service.
+	APIKeys []apikey.Key
+	// StoreFactory provides a Store for the partition named in each request.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// NewGRPCServer returns a gRPC server that serves s, along with the gRPC
+// reflection service so that tools like grpcurl can discover it.
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(s.authenticate))
+	srv := grpc.NewServer(opts...)
+	randomizerpb.RegisterRandomizerServer(srv, s)
+	reflection.Register(srv)
+	return srv
+}
+
+// Select implements randomizerpb.RandomizerServer.
+func (s *Server) Select(ctx context.Context, req *randomizerpb.SelectRequest) (*randomizerpb.SelectResponse, error) {
+	var (
+		args []string
+		ok   bool
+	)
+	switch source := req.GetSource().(type) {
+	case *randomizerpb.SelectRequest_Group:
+		// A group can't have a name that the randomizer would interpret, so we
+		// report one that does as missing.
+		if args, ok = randomizer.GroupSelectionArgs(source.Group); !ok {
+			return nil, status.Errorf(codes.NotFound, "The %q group doesn't exist.", source.Group)
+		}
+	case *randomizerpb.SelectRequest_Options:
+		if args, ok = randomizer.OptionSelectionArgs(source.Options.GetOptions()); !ok {
+			return nil, status.Error(codes.InvalidArgument, "Provide at least two options, and at least one that doesn't start with a slash.")
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Provide either a group or a list of options.")
+	}
+
+	res, err := s.run(ctx, req.GetPartition(), randomizer.Selection, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &randomizerpb.SelectResponse{Options: res.Options(), Message: res.Message()}, nil
+}
+
+// ListGroups implements randomizerpb.RandomizerServer.
+func (s *Server) ListGroups(ctx context.Context, req *randomizerpb.ListGroupsRequest) (*randomizerpb.ListGroupsResponse, error) {
+	res, err := s.run(ctx, req.GetPartition(), randomizer.ListedGroups, "/list")
+	if err != nil {
+		return nil, err
+	}
+	return &randomizerpb.ListGroupsResponse{Groups: res.Options(), Message: res.Message()}, nil
+}
+
+// GetGroup implements randomizerpb.RandomizerServer.
+func (s *Server) GetGroup(ctx context.Context, req *randomizerpb.GetGroupRequest) (*randomizerpb.Group, error) {
+	res, err := s.run(ctx, req.GetPartition(), randomizer.ShowedGroup, "/show", req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &randomizerpb.Group{Name: res.GroupName(), Options: res.Options(), Message: res.Message()}, nil
+}
+
+// PutGroup implements randomizerpb.RandomizerServer.
+func (s *Server) PutGroup(ctx context.Context, req *randomizerpb.PutGroupRequest) (*randomizerpb.Group, error) {
+	args := append([]string{"/save", req.GetName()}, req.GetOptions()...)
+	res, err := s.run(ctx, req.GetPartition(), randomizer.SavedGroup, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &randomizerpb.Group{Name: res.GroupName(), Options: res.Options(), Message: res.Message()}, nil
+}
+
+// DeleteGroup implements randomizerpb.RandomizerServer.
+func (s *Server) DeleteGroup(ctx context.Context, req *randomizerpb.DeleteGroupRequest) (*randomizerpb.DeleteGroupResponse, error) {
+	name := req.GetName()
+	res, err := s.run(ctx, req.GetPartition(), -1, "/delete", name)
+	if err != nil {
+		return nil, err
+	}
+	if res.GroupName() != "" {
+		name = res.GroupName()
+	}
+	return &randomizerpb.DeleteGroupResponse{Name: name, Message: res.Message()}, nil
+}
+
+// run runs the randomizer for a request, and checks that it produced a result
+// of the wanted type, if wantType is not negative. Errors that it returns are
+// gRPC status errors.
+func (s *Server) run(ctx context.Context, partition string, wantType randomizer.ResultType, args ...string) (randomizer.Result, error) {
+	if partition == "" {
+		return randomizer.Result{}, status.Error(codes.InvalidArgument, "A partition is required.")
+	}
+
+	options := []randomizer.Option{
+		randomizer.WithRules(s.Rules),
+		randomizer.WithUser(apiKeyName(ctx)),
+	}
+	app := randomizer.NewApp("randomizer", s.StoreFactory(partition), options...)
+	res, err := app.Main(ctx, args)
+	if err != nil {
+		return randomizer.Result{}, s.statusFor(err)
+	}
+	if wantType >= 0 && res.Type() != wantType {
+		err := fmt.Errorf("got randomizer result of type %v, want %v", res.Type(), wantType)
+		s.logErr(err, "Failed to handle gRPC request")
+		return randomizer.Result{}, status.Error(codes.Internal, "Something went wrong.")
+	}
+	return res, nil
+}
+
+// statusFor returns the gRPC status error for an error from the randomizer.
+func (s *Server) statusFor(err error) error {
+	var rerr randomizer.Error
+	if !errors.As(err, &rerr) {
+		s.logErr(err, "Failed to handle gRPC request")
+		return status.Error(codes.Internal, "Something went wrong.")
+	}
+
+	code := codes.InvalidArgument
+	switch {
+	case errors.Is(err, randomizer.ErrGroupNotFound):
+		code = codes.NotFound
+	case errors.Is(err, randomizer.ErrUnavailable):
+		code = codes.Unavailable
+		s.logErr(err, "Failed to run randomizer")
+	}
+	return status.Error(code, rerr.HelpText())
+}
+
+type apiKeyNameKey struct{}
+
+func apiKeyName(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyNameKey{}).(string)
+	return name
+}
+
+// authenticate is a unary interceptor that checks the API key of every call to
+// the Randomizer service, leaving other services like reflection open.
+func (s *Server) authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, "/"+randomizerpb.Randomizer_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			scheme, value, found := strings.Cut(values[0], " ")
+			if found && strings.EqualFold(scheme, "Bearer") {
+				token = value
+			}
+		}
+	}
+
+	key, ok := apikey.Match(s.APIKeys, token)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "A valid API key is required.")
+	}
+	return handler(context.WithValue(ctx, apiKeyNameKey{}, key.Name), req)
+}
+
+func (s *Server) logErr(err error, msg string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Error(msg, append([]any{"err", err}, args...)...)
+	}
+}
@@ -0,0 +1,234 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/featherbread/randomizer/internal/apikey"
+	"github.com/featherbread/randomizer/internal/grpcapi/randomizerpb"
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+const testKey = "test-key"
+
+// newTestClient starts a server on an in-memory listener, and returns a client
+// connection to it.
+func newTestClient(t *testing.T, stores *rndtest.Partitions) *grpc.ClientConn {
+	t.Helper()
+	server := &Server{
+		APIKeys:      []apikey.Key{{Name: "ci", Secret: testKey}},
+		StoreFactory: func(partition string) randomizer.Store { return stores.Store(partition) },
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := server.NewGRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+key)
+}
+
+func TestRandomizer(t *testing.T) {
+	stores := new(rndtest.Partitions)
+	stores.Break("broken")
+	client := randomizerpb.NewRandomizerClient(newTestClient(t, stores))
+	ctx := withKey(testKey)
+
+	group, err := client.PutGroup(ctx, &randomizerpb.PutGroupRequest{Partition: "C1", Name: "Snacks", Options: []string{"pretzels", "chips"}})
+	if err != nil {
+		t.Fatalf("PutGroup failed: %v", err)
+	}
+	if group.Name != "snacks" || !slices.Equal(group.Options, []string{"chips", "pretzels"}) {
+		t.Errorf("unexpected PutGroup result %v", group)
+	}
+
+	group, err = client.GetGroup(ctx, &randomizerpb.GetGroupRequest{Partition: "C1", Name: "snacks"})
+	if err != nil {
+		t.Fatalf("GetGroup failed: %v", err)
+	}
+	if group.Name != "snacks" || !slices.Equal(group.Options, []string{"chips", "pretzels"}) {
+		t.Errorf("unexpected GetGroup result %v", group)
+	}
+
+	list, err := client.ListGroups(ctx, &randomizerpb.ListGroupsRequest{Partition: "C1"})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	if !slices.Equal(list.Groups, []string{"snacks"}) {
+		t.Errorf("unexpected ListGroups result %v", list)
+	}
+
+	selection, err := client.Select(ctx, &randomizerpb.SelectRequest{
+		Partition: "C1",
+		Source:    &randomizerpb.SelectRequest_Group{Group: "snacks"},
+	})
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if got := slices.Sorted(slices.Values(selection.Options)); !slices.Equal(got, []string{"chips", "pretzels"}) {
+		t.Errorf("unexpected Select result %v", selection)
+	}
+
+	deleted, err := client.DeleteGroup(ctx, &randomizerpb.DeleteGroupRequest{Partition: "C1", Name: "snacks"})
+	if err != nil {
+		t.Fatalf("DeleteGroup failed: %v", err)
+	}
+	if deleted.Name != "snacks" {
+		t.Errorf("unexpected DeleteGroup result %v", deleted)
+	}
+	if store := stores.Store("C1").Store; len(store) != 0 {
+		t.Errorf("unexpected store state: %v", store)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	stores := new(rndtest.Partitions)
+	stores.Break("broken")
+	client := randomizerpb.NewRandomizerClient(newTestClient(t, stores))
+
+	testCases := []struct {
+		description string
+		ctx         context.Context
+		call        func(context.Context) error
+		want        codes.Code
+	}{
+		{
+			description: "missing key",
+			ctx:         context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := client.ListGroups(ctx, &randomizerpb.ListGroupsRequest{Partition: "C1"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			description: "wrong key",
+			ctx:         withKey("wrong"),
+			call: func(ctx context.Context) error {
+				_, err := client.ListGroups(ctx, &randomizerpb.ListGroupsRequest{Partition: "C1"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			description: "missing partition",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.ListGroups(ctx, &randomizerpb.ListGroupsRequest{})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			description: "missing group",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.GetGroup(ctx, &randomizerpb.GetGroupRequest{Partition: "C1", Name: "nope"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			description: "selecting from a flag-like group",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.Select(ctx, &randomizerpb.SelectRequest{
+					Partition: "C1",
+					Source:    &randomizerpb.SelectRequest_Group{Group: "/list"},
+				})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			description: "selecting from too few options",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.Select(ctx, &randomizerpb.SelectRequest{
+					Partition: "C1",
+					Source:    &randomizerpb.SelectRequest_Options{Options: &randomizerpb.Options{Options: []string{"one"}}},
+				})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			description: "saving too few options",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.PutGroup(ctx, &randomizerpb.PutGroupRequest{Partition: "C1", Name: "lonely", Options: []string{"one"}})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			description: "store failure",
+			ctx:         withKey(testKey),
+			call: func(ctx context.Context) error {
+				_, err := client.ListGroups(ctx, &randomizerpb.ListGroupsRequest{Partition: "broken"})
+				return err
+			},
+			want: codes.Unavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.call(tc.ctx)
+			if got := status.Code(err); got != tc.want {
+				t.Errorf("got code %v, want %v (error %v)", got, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestReflection(t *testing.T) {
+	conn := newTestClient(t, new(rndtest.Partitions))
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	if !slices.Contains(services, randomizerpb.Randomizer_ServiceDesc.ServiceName) {
+		t.Errorf("reflection lists services %v, missing the randomizer", services)
+	}
+}
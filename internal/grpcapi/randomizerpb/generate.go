@@ -0,0 +1,5 @@
+// Package randomizerpb holds the protocol buffer and gRPC code generated from
+// randomizer.proto.
+package randomizerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative randomizer.proto
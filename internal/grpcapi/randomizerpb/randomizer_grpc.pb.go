@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: randomizer.proto
+
+package randomizerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Randomizer_Select_FullMethodName      = "/randomizer.v1.Randomizer/Select"
+	Randomizer_ListGroups_FullMethodName  = "/randomizer.v1.Randomizer/ListGroups"
+	Randomizer_GetGroup_FullMethodName    = "/randomizer.v1.Randomizer/GetGroup"
+	Randomizer_PutGroup_FullMethodName    = "/randomizer.v1.Randomizer/PutGroup"
+	Randomizer_DeleteGroup_FullMethodName = "/randomizer.v1.Randomizer/DeleteGroup"
+)
+
+// RandomizerClient is the client API for Randomizer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Randomizer randomizes lists of options, and manages the groups of options
+// saved in each partition.
+//
+// Partitions are named as in the frontends that create them, like "C1234" or
+// "T1234:C1234" for Slack channels, or "discord:1234:5678" for Discord
+// channels.
+type RandomizerClient interface {
+	// Select randomizes the options of a group, or a list of options.
+	Select(ctx context.Context, in *SelectRequest, opts ...grpc.CallOption) (*SelectResponse, error)
+	// ListGroups lists the names of the groups in a partition.
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// GetGroup gets the options of a group, or of the group that an alias refers
+	// to.
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// PutGroup saves a group, replacing the options of any existing group with
+	// its name.
+	PutGroup(ctx context.Context, in *PutGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// DeleteGroup deletes a group along with its aliases, or deletes an alias
+	// alone.
+	DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupResponse, error)
+}
+
+type randomizerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRandomizerClient(cc grpc.ClientConnInterface) RandomizerClient {
+	return &randomizerClient{cc}
+}
+
+func (c *randomizerClient) Select(ctx context.Context, in *SelectRequest, opts ...grpc.CallOption) (*SelectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectResponse)
+	err := c.cc.Invoke(ctx, Randomizer_Select_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomizerClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, Randomizer_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomizerClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, Randomizer_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomizerClient) PutGroup(ctx context.Context, in *PutGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, Randomizer_PutGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomizerClient) DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteGroupResponse)
+	err := c.cc.Invoke(ctx, Randomizer_DeleteGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RandomizerServer is the server API for Randomizer service.
+// All implementations must embed UnimplementedRandomizerServer
+// for forward compatibility.
+//
+// Randomizer randomizes lists of options, and manages the groups of options
+// saved in each partition.
+//
+// Partitions are named as in the frontends that create them, like "C1234" or
+// "T1234:C1234" for Slack channels, or "discord:1234:5678" for Discord
+// channels.
+type RandomizerServer interface {
+	// Select randomizes the options of a group, or a list of options.
+	Select(context.Context, *SelectRequest) (*SelectResponse, error)
+	// ListGroups lists the names of the groups in a partition.
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	// GetGroup gets the options of a group, or of the group that an alias refers
+	// to.
+	GetGroup(context.Context, *GetGroupRequest) (*Group, error)
+	// PutGroup saves a group, replacing the options of any existing group with
+	// its name.
+	PutGroup(context.Context, *PutGroupRequest) (*Group, error)
+	// DeleteGroup deletes a group along with its aliases, or deletes an alias
+	// alone.
+	DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupResponse, error)
+	mustEmbedUnimplementedRandomizerServer()
+}
+
+// UnimplementedRandomizerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRandomizerServer struct{}
+
+func (UnimplementedRandomizerServer) Select(context.Context, *SelectRequest) (*SelectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Select not implemented")
+}
+func (UnimplementedRandomizerServer) ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedRandomizerServer) GetGroup(context.Context, *GetGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedRandomizerServer) PutGroup(context.Context, *PutGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutGroup not implemented")
+}
+func (UnimplementedRandomizerServer) DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedRandomizerServer) mustEmbedUnimplementedRandomizerServer() {}
+func (UnimplementedRandomizerServer) testEmbeddedByValue()                    {}
+
+// UnsafeRandomizerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RandomizerServer will
+// result in compilation errors.
+type UnsafeRandomizerServer interface {
+	mustEmbedUnimplementedRandomizerServer()
+}
+
+func RegisterRandomizerServer(s grpc.ServiceRegistrar, srv RandomizerServer) {
+	// If the following call pancis, it indicates UnimplementedRandomizerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Randomizer_ServiceDesc, srv)
+}
+
+func _Randomizer_Select_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomizerServer).Select(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Randomizer_Select_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomizerServer).Select(ctx, req.(*SelectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Randomizer_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomizerServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Randomizer_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomizerServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Randomizer_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomizerServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Randomizer_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomizerServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Randomizer_PutGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomizerServer).PutGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Randomizer_PutGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomizerServer).PutGroup(ctx, req.(*PutGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Randomizer_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomizerServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Randomizer_DeleteGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomizerServer).DeleteGroup(ctx, req.(*DeleteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Randomizer_ServiceDesc is the grpc.ServiceDesc for Randomizer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Randomizer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "randomizer.v1.Randomizer",
+	HandlerType: (*RandomizerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Select",
+			Handler:    _Randomizer_Select_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _Randomizer_ListGroups_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _Randomizer_GetGroup_Handler,
+		},
+		{
+			MethodName: "PutGroup",
+			Handler:    _Randomizer_PutGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _Randomizer_DeleteGroup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "randomizer.proto",
+}
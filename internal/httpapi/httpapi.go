@@ -0,0 +1,404 @@
+// Package httpapi serves a JSON REST API for the randomizer, for use by CI
+// jobs and other tools outside of a chat service.
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/apikey"
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// App serves the randomizer's JSON REST API.
+//
+// Every request except the one for the OpenAPI description of the API must
+// present one of the API keys as a bearer token, unless the App authenticates
+// requests in some other way. Access to the API grants full access to the
+// groups of every partition.
+type App struct {
+	// APIKeys lists the keys that grant access to the API.
+	APIKeys []apikey.Key
+	// Authenticate, if non-nil, authenticates requests in place of APIKeys. It
+	// returns the name of the user making the request, which is recorded as the
+	// creator of the groups that it saves.
+	Authenticate func(r *http.Request) (user string, ok bool)
+	// StoreFactory provides a Store for the partition named in the request path.
+	// Partitions are named as in the frontends that create them, like "C1234" or
+	// "T1234:C1234" for Slack channels, or "discord:1234:5678" for Discord
+	// channels.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// Handler returns a handler for the API, which serves paths beginning with
+// /v1/.
+func (a App) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /v1/openapi.json", http.HandlerFunc(a.serveOpenAPI))
+	for _, rt := range routes {
+		mux.Handle(rt.Method+" "+rt.Path, a.routeHandler(rt))
+	}
+	return mux
+}
+
+// maxBodySize limits the size of the request bodies that App reads.
+const maxBodySize = 1 << 20
+
+// route describes an operation of the API, both for serving it and for
+// describing it in the OpenAPI document.
+type route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	// Request is the type of the request body, or nil if the operation takes no
+	// body.
+	Request any
+	// Response is the type of the successful response body.
+	Response any
+	// Errors lists the statuses of the error responses that the operation
+	// returns, other than for authentication failures.
+	Errors []int
+	// Handle performs the operation. It receives the decoded request body, if
+	// any, as a pointer to a value of the Request type.
+	Handle func(a App, r *http.Request, body any) (any, error)
+}
+
+var routes = []route{
+	{
+		Method:      http.MethodGet,
+		Path:        "/v1/partitions",
+		OperationID: "listPartitions",
+		Summary:     "List the partitions with saved data, if the storage backend supports it.",
+		Response:    partitionList{},
+		Errors:      []int{http.StatusNotImplemented, http.StatusServiceUnavailable},
+		Handle:      App.listPartitions,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/v1/partitions/{partition}/groups",
+		OperationID: "listGroups",
+		Summary:     "List the names of the groups in a partition.",
+		Response:    groupList{},
+		Errors:      []int{http.StatusServiceUnavailable},
+		Handle:      App.listGroups,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/v1/partitions/{partition}/groups/{group}",
+		OperationID: "getGroup",
+		Summary:     "Get the options of a group, or of the group that an alias refers to.",
+		Response:    group{},
+		Errors:      []int{http.StatusNotFound, http.StatusServiceUnavailable},
+		Handle:      App.getGroup,
+	},
+	{
+		Method:      http.MethodPut,
+		Path:        "/v1/partitions/{partition}/groups/{group}",
+		OperationID: "putGroup",
+		Summary:     "Save a group, replacing the options of any existing group with its name.",
+		Request:     groupInput{},
+		Response:    group{},
+		Errors:      []int{http.StatusBadRequest, http.StatusServiceUnavailable},
+		Handle:      App.putGroup,
+	},
+	{
+		Method:      http.MethodDelete,
+		Path:        "/v1/partitions/{partition}/groups/{group}",
+		OperationID: "deleteGroup",
+		Summary:     "Delete a group along with its aliases, or delete an alias alone.",
+		Response:    deletedGroup{},
+		Errors:      []int{http.StatusNotFound, http.StatusServiceUnavailable},
+		Handle:      App.deleteGroup,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/v1/partitions/{partition}/select",
+		OperationID: "select",
+		Summary:     "Randomize the options of a group, or a list of options.",
+		Request:     selectionInput{},
+		Response:    selection{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+		Handle:      App.selectOptions,
+	},
+}
+
+type partitionList struct {
+	Partitions []string `json:"partitions" doc:"The names of the partitions, in sorted order."`
+}
+
+type groupList struct {
+	Groups  []string `json:"groups" doc:"The names of the groups, in sorted order."`
+	Message string   `json:"message" doc:"A human-readable description of the result."`
+}
+
+type group struct {
+	Name    string   `json:"name" doc:"The name of the group."`
+	Options []string `json:"options" doc:"The options in the group, in sorted order."`
+	Message string   `json:"message" doc:"A human-readable description of the result."`
+}
+
+type groupInput struct {
+	Options []string `json:"options" doc:"The options to save in the group."`
+}
+
+type deletedGroup struct {
+	Name    string `json:"name" doc:"The name of the deleted group or alias."`
+	Message string `json:"message" doc:"A human-readable description of the result."`
+}
+
+type selectionInput struct {
+	Group   string   `json:"group,omitempty" doc:"The name of a group to randomize. Exactly one of group and options is required."`
+	Options []string `json:"options,omitempty" doc:"At least two options to randomize. Exactly one of group and options is required."`
+}
+
+type selection struct {
+	Options []string `json:"options" doc:"The randomized options, in their selected order."`
+	Message string   `json:"message" doc:"A human-readable description of the result."`
+}
+
+type errorResponse struct {
+	Error string `json:"error" doc:"A human-readable explanation of the error."`
+}
+
+// partitionLister is implemented by stores that can list every partition in
+// their database, like the slack package's PartitionLister.
+type partitionLister interface {
+	ListPartitions(ctx context.Context) (partitions []string, err error)
+}
+
+// listingPartition names the store that listPartitions asks for the list of
+// partitions. A store lists every partition in its database, whichever one it
+// was made for, but some backends can't make a store for an empty name.
+const listingPartition = "partitions"
+
+func (a App) listPartitions(r *http.Request, _ any) (any, error) {
+	lister, ok := a.StoreFactory(listingPartition).(partitionLister)
+	if !ok {
+		return nil, apiError{http.StatusNotImplemented, "The storage backend can't list partitions."}
+	}
+	partitions, err := lister.ListPartitions(r.Context())
+	if err != nil {
+		a.logErr(err, "Failed to list partitions")
+		return nil, apiError{http.StatusServiceUnavailable, "Listing partitions failed. Please try again later."}
+	}
+	if partitions == nil {
+		partitions = []string{}
+	}
+	slices.Sort(partitions)
+	return partitionList{Partitions: partitions}, nil
+}
+
+func (a App) listGroups(r *http.Request, _ any) (any, error) {
+	res, err := a.run(r, randomizer.ListedGroups, "/list")
+	if err != nil {
+		return nil, err
+	}
+	groups := res.Options()
+	if groups == nil {
+		groups = []string{}
+	}
+	return groupList{Groups: groups, Message: res.Message()}, nil
+}
+
+func (a App) getGroup(r *http.Request, _ any) (any, error) {
+	res, err := a.run(r, randomizer.ShowedGroup, "/show", r.PathValue("group"))
+	if err != nil {
+		return nil, err
+	}
+	return group{Name: res.GroupName(), Options: res.Options(), Message: res.Message()}, nil
+}
+
+func (a App) putGroup(r *http.Request, body any) (any, error) {
+	input := body.(*groupInput)
+	args := append([]string{"/save", r.PathValue("group")}, input.Options...)
+	res, err := a.run(r, randomizer.SavedGroup, args...)
+	if err != nil {
+		return nil, err
+	}
+	return group{Name: res.GroupName(), Options: res.Options(), Message: res.Message()}, nil
+}
+
+func (a App) deleteGroup(r *http.Request, _ any) (any, error) {
+	name := r.PathValue("group")
+	res, err := a.run(r, -1, "/delete", name)
+	if err != nil {
+		return nil, err
+	}
+	if res.GroupName() != "" {
+		name = res.GroupName()
+	}
+	return deletedGroup{Name: name, Message: res.Message()}, nil
+}
+
+func (a App) selectOptions(r *http.Request, body any) (any, error) {
+	input := body.(*selectionInput)
+
+	var (
+		args []string
+		ok   bool
+	)
+	switch {
+	case input.Group != "" && len(input.Options) == 0:
+		// A group can't have a name that the randomizer would interpret, so we
+		// report one that does as missing.
+		if args, ok = randomizer.GroupSelectionArgs(input.Group); !ok {
+			return nil, apiError{http.StatusNotFound, fmt.Sprintf("The %q group doesn't exist.", input.Group)}
+		}
+	case input.Group == "":
+		if args, ok = randomizer.OptionSelectionArgs(input.Options); !ok {
+			return nil, apiError{http.StatusBadRequest, "Provide at least two options, and at least one that doesn't start with a slash."}
+		}
+	default:
+		return nil, apiError{http.StatusBadRequest, "Provide either a group or a list of options, but not both."}
+	}
+
+	res, err := a.run(r, randomizer.Selection, args...)
+	if err != nil {
+		return nil, err
+	}
+	return selection{Options: res.Options(), Message: res.Message()}, nil
+}
+
+// run runs the randomizer for a request, and checks that it produced a result
+// of the wanted type, if wantType is not negative.
+func (a App) run(r *http.Request, wantType randomizer.ResultType, args ...string) (randomizer.Result, error) {
+	options := []randomizer.Option{
+		randomizer.WithRules(a.Rules),
+		randomizer.WithUser(userName(r.Context())),
+	}
+	app := randomizer.NewApp("randomizer", a.StoreFactory(r.PathValue("partition")), options...)
+	res, err := app.Main(r.Context(), args)
+	if err != nil {
+		return randomizer.Result{}, err
+	}
+	if wantType >= 0 && res.Type() != wantType {
+		return randomizer.Result{}, fmt.Errorf("got randomizer result of type %v, want %v", res.Type(), wantType)
+	}
+	return res, nil
+}
+
+// apiError is an error with a specific HTTP status, whose message is shown to
+// clients.
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e apiError) Error() string {
+	return e.Message
+}
+
+type userNameKey struct{}
+
+func userName(ctx context.Context) string {
+	name, _ := ctx.Value(userNameKey{}).(string)
+	return name
+}
+
+// routeHandler returns the handler for a route, which authenticates the
+// request, decodes its body, and encodes the response or error.
+func (a App) routeHandler(rt route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := a.authenticate(r)
+		if !ok {
+			w.Header().Add("WWW-Authenticate", "Bearer")
+			a.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "A valid API key is required."})
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), userNameKey{}, user))
+
+		var body any
+		if rt.Request != nil {
+			body = newOf(rt.Request)
+			decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(body); err != nil {
+				a.writeError(w, apiError{http.StatusBadRequest, fmt.Sprintf("The request body is not valid: %v.", err)})
+				return
+			}
+		}
+
+		resp, err := rt.Handle(a, r, body)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+		a.writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// authenticate returns the name of the user making the request, if it is
+// authenticated.
+func (a App) authenticate(r *http.Request) (user string, ok bool) {
+	if a.Authenticate != nil {
+		return a.Authenticate(r)
+	}
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	key, ok := apikey.Match(a.APIKeys, token)
+	return key.Name, ok
+}
+
+// writeError writes the response for an error, choosing a status from its
+// type.
+func (a App) writeError(w http.ResponseWriter, err error) {
+	var (
+		aerr apiError
+		rerr randomizer.Error
+	)
+	switch {
+	case errors.As(err, &aerr):
+		a.writeJSON(w, aerr.Status, errorResponse{Error: aerr.Message})
+
+	case errors.As(err, &rerr):
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, randomizer.ErrGroupNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, randomizer.ErrUnavailable):
+			status = http.StatusServiceUnavailable
+			a.logErr(err, "Failed to run randomizer")
+		}
+		a.writeJSON(w, status, errorResponse{Error: rerr.HelpText()})
+
+	default:
+		a.logErr(err, "Failed to handle API request")
+		a.writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "Something went wrong."})
+	}
+}
+
+func (a App) writeJSON(w http.ResponseWriter, status int, body any) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		a.logErr(err, "Failed to encode response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		a.logErr(err, "Failed to write response")
+	}
+}
+
+func (a App) logErr(err error, msg string, args ...any) {
+	if a.Logger != nil {
+		a.Logger.Error(msg, append([]any{"err", err}, args...)...)
+	}
+}
@@ -0,0 +1,338 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/apikey"
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+	"github.com/featherbread/randomizer/internal/store/bbolt"
+)
+
+const testKey = "test-key"
+
+func newTestApp() (App, *rndtest.Partitions) {
+	stores := new(rndtest.Partitions)
+	stores.Break("broken")
+	app := App{
+		APIKeys:      []apikey.Key{{Name: "ci", Secret: testKey}},
+		StoreFactory: func(partition string) randomizer.Store { return stores.Store(partition) },
+	}
+	return app, stores
+}
+
+func request(method, path, key, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	return req
+}
+
+func TestAPI(t *testing.T) {
+	app, stores := newTestApp()
+	handler := app.Handler()
+
+	testCases := []struct {
+		description string
+		req         *http.Request
+		wantStatus  int
+		wantBody    map[string]any
+	}{
+		{
+			description: "missing key",
+			req:         request(http.MethodGet, "/v1/partitions/C1/groups/snacks", "", ""),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "wrong key",
+			req:         request(http.MethodGet, "/v1/partitions/C1/groups/snacks", "wrong", ""),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "getting a missing group",
+			req:         request(http.MethodGet, "/v1/partitions/C1/groups/snacks", testKey, ""),
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			description: "saving a group",
+			req:         request(http.MethodPut, "/v1/partitions/C1/groups/Snacks", testKey, `{"options":["pretzels","chips"]}`),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"name": "snacks", "options": []any{"chips", "pretzels"}},
+		},
+		{
+			description: "saving a group with too few options",
+			req:         request(http.MethodPut, "/v1/partitions/C1/groups/lonely", testKey, `{"options":["one"]}`),
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "saving a group with an invalid body",
+			req:         request(http.MethodPut, "/v1/partitions/C1/groups/snacks", testKey, `{"opts":["one","two"]}`),
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "listing groups",
+			req:         request(http.MethodGet, "/v1/partitions/C1/groups", testKey, ""),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"groups": []any{"snacks"}},
+		},
+		{
+			description: "listing groups in an empty partition",
+			req:         request(http.MethodGet, "/v1/partitions/C2/groups", testKey, ""),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"groups": []any{}},
+		},
+		{
+			description: "listing partitions",
+			req:         request(http.MethodGet, "/v1/partitions", testKey, ""),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"partitions": []any{"C1"}},
+		},
+		{
+			description: "getting a group",
+			req:         request(http.MethodGet, "/v1/partitions/C1/groups/snacks", testKey, ""),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"name": "snacks", "options": []any{"chips", "pretzels"}},
+		},
+		{
+			description: "selecting from a group",
+			req:         request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"group":"snacks"}`),
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "selecting from a missing group",
+			req:         request(http.MethodPost, "/v1/partitions/C2/select", testKey, `{"group":"snacks"}`),
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			description: "selecting from a flag-like group",
+			req:         request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"group":"/list"}`),
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			description: "selecting from flag-like options",
+			req:         request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"options":["/save","two"]}`),
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "selecting from too few options",
+			req:         request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"options":["one"]}`),
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "selecting from both a group and options",
+			req:         request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"group":"snacks","options":["one","two"]}`),
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "store failure",
+			req:         request(http.MethodGet, "/v1/partitions/broken/groups/snacks", testKey, ""),
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			description: "deleting a group",
+			req:         request(http.MethodDelete, "/v1/partitions/C1/groups/snacks", testKey, ""),
+			wantStatus:  http.StatusOK,
+			wantBody:    map[string]any{"name": "snacks"},
+		},
+		{
+			description: "deleting a missing group",
+			req:         request(http.MethodDelete, "/v1/partitions/C1/groups/snacks", testKey, ""),
+			wantStatus:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, tc.req)
+			if resp.Code != tc.wantStatus {
+				t.Fatalf("got status %d, want %d\n%s", resp.Code, tc.wantStatus, resp.Body)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tc.wantStatus != http.StatusOK {
+				if msg, _ := body["error"].(string); msg == "" {
+					t.Errorf("error response has no message: %v", body)
+				}
+				return
+			}
+			for key, want := range tc.wantBody {
+				if !reflect.DeepEqual(body[key], want) {
+					t.Errorf("got %s = %v, want %v", key, body[key], want)
+				}
+			}
+		})
+	}
+
+	if store := stores.Store("C1").Store; !reflect.DeepEqual(store, rndtest.Store{}) {
+		t.Errorf("unexpected store state: %v", store)
+	}
+}
+
+func TestSelectionOptions(t *testing.T) {
+	app, _ := newTestApp()
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodPost, "/v1/partitions/C1/select", testKey, `{"options":["one","two","three"]}`))
+
+	var body selection
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	got := slices.Sorted(slices.Values(body.Options))
+	if want := []string{"one", "three", "two"}; !slices.Equal(got, want) {
+		t.Errorf("got options %v, want a permutation of %v", body.Options, want)
+	}
+}
+
+func TestCreatorFromKey(t *testing.T) {
+	store := rndtest.NewGroupStore()
+	app := App{
+		APIKeys:      []apikey.Key{{Name: "other", Secret: "other-key"}, {Name: "ci", Secret: testKey}},
+		StoreFactory: func(string) randomizer.Store { return store },
+	}
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodPut, "/v1/partitions/C1/groups/snacks", testKey, `{"options":["chips","pretzels"]}`))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+	if got := store.Metadata["snacks"].Creator; got != "ci" {
+		t.Errorf("got creator %q, want %q", got, "ci")
+	}
+}
+
+func TestListPartitions(t *testing.T) {
+	var partitions rndtest.Partitions
+	partitions.Store("C2").Put(context.Background(), "snacks", []string{"chips", "pretzels"})
+	partitions.Store("C1").Put(context.Background(), "lunch", []string{"tacos", "pizza"})
+	app := App{
+		APIKeys:      []apikey.Key{{Name: "ci", Secret: testKey}},
+		StoreFactory: func(partition string) randomizer.Store { return partitions.Store(partition) },
+	}
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodGet, "/v1/partitions", testKey, ""))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+	var body partitionList
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if want := []string{"C1", "C2"}; !slices.Equal(body.Partitions, want) {
+		t.Errorf("got partitions %v, want %v", body.Partitions, want)
+	}
+}
+
+func TestListPartitionsUnsupported(t *testing.T) {
+	app := App{
+		APIKeys:      []apikey.Key{{Name: "ci", Secret: testKey}},
+		StoreFactory: func(string) randomizer.Store { return make(rndtest.Store) },
+	}
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodGet, "/v1/partitions", testKey, ""))
+	if resp.Code != http.StatusNotImplemented {
+		t.Errorf("got status %d, want %d", resp.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestListPartitionsBbolt(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "randomizer.db"))
+	factory, err := bbolt.FactoryFromEnv(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := App{APIKeys: []apikey.Key{{Name: "ci", Secret: testKey}}, StoreFactory: factory}
+	for _, partition := range []string{"C2", "C1"} {
+		resp := httptest.NewRecorder()
+		app.Handler().ServeHTTP(resp, request(http.MethodPut, "/v1/partitions/"+partition+"/groups/snacks", testKey, `{"options":["chips","pretzels"]}`))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("saving group in %s got status %d, want %d", partition, resp.Code, http.StatusOK)
+		}
+	}
+
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodGet, "/v1/partitions", testKey, ""))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+	var body partitionList
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if want := []string{"C1", "C2"}; !slices.Equal(body.Partitions, want) {
+		t.Errorf("got partitions %v, want %v", body.Partitions, want)
+	}
+}
+
+func TestCustomAuthentication(t *testing.T) {
+	store := rndtest.NewGroupStore()
+	app := App{
+		APIKeys: []apikey.Key{{Name: "ci", Secret: testKey}},
+		Authenticate: func(r *http.Request) (string, bool) {
+			user := r.Header.Get("X-User")
+			return user, user != ""
+		},
+		StoreFactory: func(string) randomizer.Store { return store },
+	}
+
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, request(http.MethodGet, "/v1/partitions/C1/groups", testKey, ""))
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("API key got status %d, want %d", resp.Code, http.StatusUnauthorized)
+	}
+
+	req := request(http.MethodPut, "/v1/partitions/C1/groups/snacks", "", `{"options":["chips","pretzels"]}`)
+	req.Header.Set("X-User", "alice")
+	resp = httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+	if got := store.Metadata["snacks"].Creator; got != "alice" {
+		t.Errorf("got creator %q, want %q", got, "alice")
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	app, _ := newTestApp()
+	resp := httptest.NewRecorder()
+	app.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("got OpenAPI version %q", doc.OpenAPI)
+	}
+	for _, rt := range routes {
+		op, ok := doc.Paths[rt.Path][strings.ToLower(rt.Method)].(map[string]any)
+		if !ok {
+			t.Errorf("document is missing %s %s", rt.Method, rt.Path)
+			continue
+		}
+		if op["operationId"] != rt.OperationID {
+			t.Errorf("got operationId %v for %s %s", op["operationId"], rt.Method, rt.Path)
+		}
+		if _, hasBody := op["requestBody"]; hasBody != (rt.Request != nil) {
+			t.Errorf("%s %s has unexpected request body presence %v", rt.Method, rt.Path, hasBody)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// serveOpenAPI serves an OpenAPI 3.1 description of the API, generated from
+// the routes that the API serves.
+func (a App) serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	a.writeJSON(w, http.StatusOK, openAPI())
+}
+
+// openAPI returns the OpenAPI document describing the API.
+func openAPI() map[string]any {
+	paths := make(map[string]any)
+	for _, rt := range routes {
+		item, ok := paths[rt.Path].(map[string]any)
+		if !ok {
+			item = map[string]any{"parameters": pathParameters(rt.Path)}
+			paths[rt.Path] = item
+		}
+		item[strings.ToLower(rt.Method)] = operation(rt)
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Randomizer API",
+			"version": "1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []any{map[string]any{"apiKey": []string{}}},
+	}
+}
+
+func operation(rt route) map[string]any {
+	responses := map[string]any{
+		"200": jsonContent("Success.", rt.Response),
+		"401": jsonContent(http.StatusText(http.StatusUnauthorized)+".", errorResponse{}),
+	}
+	for _, status := range rt.Errors {
+		responses[strconv.Itoa(status)] = jsonContent(http.StatusText(status)+".", errorResponse{})
+	}
+
+	op := map[string]any{
+		"operationId": rt.OperationID,
+		"summary":     rt.Summary,
+		"responses":   responses,
+	}
+	if rt.Request != nil {
+		body := jsonContent("", rt.Request)
+		delete(body, "description")
+		body["required"] = true
+		op["requestBody"] = body
+	}
+	return op
+}
+
+func jsonContent(description string, v any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaOf(reflect.TypeOf(v))},
+		},
+	}
+}
+
+// pathParameters describes the wildcards of a route's path pattern.
+func pathParameters(path string) []any {
+	var params []any
+	for segment := range strings.SplitSeq(path, "/") {
+		name, ok := strings.CutPrefix(segment, "{")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(name, "}")
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	return params
+}
+
+// schemaOf returns a JSON Schema describing the JSON encoding of values of the
+// given type. It supports only the kinds of types that the API uses.
+func schemaOf(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := []string{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			schema := schemaOf(field.Type)
+			if doc := field.Tag.Get("doc"); doc != "" {
+				schema["description"] = doc
+			}
+			properties[name] = schema
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		panic("httpapi: no schema for type " + t.String())
+	}
+}
+
+// newOf returns a pointer to a new zero value of the same type as v.
+func newOf(v any) any {
+	return reflect.New(reflect.TypeOf(v)).Interface()
+}
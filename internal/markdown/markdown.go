@@ -0,0 +1,31 @@
+// Package markdown renders the randomizer's messages for frontends that use
+// Markdown or plain text.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// boldPattern matches text in bold in the randomizer's messages.
+var boldPattern = regexp.MustCompile(`\*([^*\n]+)\*`)
+
+// htmlEntities replaces the HTML entities in the randomizer's messages.
+var htmlEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// FromMrkdwn renders a message from the randomizer, which is written in
+// Slack's mrkdwn format, in common Markdown. The formats mostly agree, but
+// Markdown shows single asterisks as italics rather than bold, and doesn't
+// decode HTML entities.
+func FromMrkdwn(message string) string {
+	message = boldPattern.ReplaceAllString(message, "**$1**")
+	return htmlEntities.Replace(message)
+}
+
+// PlainText renders a message from the randomizer, which is written in Slack's
+// mrkdwn format, as plain text, without the asterisks around bold text and
+// with HTML entities decoded.
+func PlainText(message string) string {
+	message = boldPattern.ReplaceAllString(message, "$1")
+	return htmlEntities.Replace(message)
+}
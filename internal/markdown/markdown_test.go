@@ -0,0 +1,37 @@
+package markdown
+
+import "testing"
+
+func TestFromMrkdwn(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"I randomized and got: *two*, *one*.", "I randomized and got: **two**, **one**."},
+		{"&gt; quoted &lt;text&gt; &amp;gt;", "> quoted <text> &gt;"},
+		{"*Example:* /randomize one two", "**Example:** /randomize one two"},
+		{"_italic_ stays", "_italic_ stays"},
+	}
+	for _, tc := range testCases {
+		if got := FromMrkdwn(tc.input); got != tc.want {
+			t.Errorf("FromMrkdwn(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"I randomized and got: *two*, *one*.", "I randomized and got: two, one."},
+		{"&gt; quoted &lt;text&gt; &amp;gt;", "> quoted <text> &gt;"},
+		{"*Example:* /randomize one two", "Example: /randomize one two"},
+		{"_italic_ stays", "_italic_ stays"},
+	}
+	for _, tc := range testCases {
+		if got := PlainText(tc.input); got != tc.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package mattermost supports invoking the randomizer as a Mattermost slash
+// command.
+package mattermost
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/markdown"
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+// TokenProvider provides the token that Mattermost generated for the slash
+// command, which it includes in every request.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// TokenProviderFromEnv returns a TokenProvider for the token in
+// MATTERMOST_TOKEN. If it is unset, TokenProviderFromEnv returns a nil
+// TokenProvider and a nil error, as Mattermost support is optional.
+func TokenProviderFromEnv() (TokenProvider, error) {
+	token, ok := os.LookupEnv("MATTERMOST_TOKEN")
+	if !ok {
+		return nil, nil
+	}
+	if token == "" {
+		return nil, errors.New("MATTERMOST_TOKEN is empty")
+	}
+	return StaticToken(token), nil
+}
+
+// StaticToken uses token as the expected value of the slash command token.
+func StaticToken(token string) TokenProvider {
+	return func(_ context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// App serves the randomizer as a Mattermost slash command.
+//
+// Mattermost's slash commands resemble Slack's, but App responds in
+// Mattermost's Markdown format rather than Slack's mrkdwn, and accepts the
+// command's token from either the Authorization header or the form.
+type App struct {
+	// TokenProvider provides the expected value of the slash command token.
+	TokenProvider TokenProvider
+	// StoreFactory provides a Store for the Mattermost channel in which the
+	// request was made. Partitions are named by the IDs of the team and the
+	// channel, separated by colons and prefixed with "mattermost:" to keep them
+	// apart from those of other frontends.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// response represents the JSON response to a slash command.
+type response struct {
+	Type string `json:"response_type"`
+	Text string `json:"text"`
+}
+
+// ServeHTTP serves POST requests from Mattermost.
+func (a App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Add("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, webhook.MaxBodySize)
+	if err := r.ParseForm(); err != nil {
+		webhook.LogError(a.Logger, err, "Failed to read POST form")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ok, err := a.isTokenValid(r)
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to verify request")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	webhook.WriteJSON(w, a.Logger, a.handleCommand(r.Context(), r.PostForm.Get))
+}
+
+// isTokenValid checks the token of a request, which Mattermost sends in both
+// the Authorization header and the form.
+func (a App) isTokenValid(r *http.Request) (ok bool, _ error) {
+	gotToken, found := strings.CutPrefix(r.Header.Get("Authorization"), "Token ")
+	if !found {
+		gotToken = r.PostForm.Get("token")
+	}
+	if gotToken == "" || a.TokenProvider == nil {
+		return false, nil
+	}
+
+	wantToken, err := a.TokenProvider(r.Context())
+	if err != nil {
+		return false, err
+	}
+	subtle.WithDataIndependentTiming(func() {
+		ok = subtle.ConstantTimeCompare([]byte(gotToken), []byte(wantToken)) == 1
+	})
+	return
+}
+
+// handleCommand runs the randomizer for a verified slash command, and returns
+// the response to show to the user.
+func (a App) handleCommand(ctx context.Context, param func(string) string) response {
+	options := []randomizer.Option{randomizer.WithRules(a.Rules)}
+	if userName := param("user_name"); userName != "" {
+		options = append(options, randomizer.WithUser("@"+userName))
+	}
+
+	partition := "mattermost:" + param("team_id") + ":" + param("channel_id")
+	app := randomizer.NewApp(param("command"), a.StoreFactory(partition), options...)
+	result, err := app.Main(ctx, strings.Fields(param("text")))
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to run randomizer")
+		return response{Type: "ephemeral", Text: markdown.FromMrkdwn(webhook.HelpText(err))}
+	}
+
+	rtype := "ephemeral"
+	if result.Type().IsPublic() {
+		rtype = "in_channel"
+	}
+	return response{Type: rtype, Text: markdown.FromMrkdwn(result.Message())}
+}
@@ -0,0 +1,125 @@
+package mattermost
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func commandParams(text string) url.Values {
+	return url.Values{
+		"command":    {"/randomize"},
+		"team_id":    {"team1"},
+		"channel_id": {"channel1"},
+		"user_name":  {"alice"},
+		"text":       {text},
+	}
+}
+
+func TestMattermost(t *testing.T) {
+	stores := new(rndtest.Partitions)
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(partition string) randomizer.Store { return stores.Store(partition) },
+	}
+
+	testCases := []struct {
+		description string
+		header      string
+		formToken   string
+		text        string
+		wantStatus  int
+		wantType    string
+		wantText    string
+	}{
+		{
+			description: "saving a group with a header token",
+			header:      "Token right",
+			text:        "/save snacks chips pretzels",
+			wantStatus:  http.StatusOK,
+			wantType:    "in_channel",
+			wantText:    `The "snacks" group was saved`,
+		},
+		{
+			description: "using a group with a form token",
+			formToken:   "right",
+			text:        "snacks",
+			wantStatus:  http.StatusOK,
+			wantType:    "in_channel",
+			wantText:    "**chips**",
+		},
+		{
+			description: "listing groups",
+			header:      "Token right",
+			text:        "/list",
+			wantStatus:  http.StatusOK,
+			wantType:    "ephemeral",
+			wantText:    "snacks",
+		},
+		{
+			description: "error",
+			header:      "Token right",
+			text:        "/delete nothing",
+			wantStatus:  http.StatusOK,
+			wantType:    "ephemeral",
+			wantText:    "Whoops",
+		},
+		{
+			description: "wrong token",
+			header:      "Token wrong",
+			formToken:   "right",
+			text:        "one two",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "missing token",
+			text:        "one two",
+			wantStatus:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			params := commandParams(tc.text)
+			if tc.formToken != "" {
+				params.Set("token", tc.formToken)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/mattermost", strings.NewReader(params.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, req)
+			if resp.Code != tc.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.Code, tc.wantStatus)
+			}
+			if tc.wantText == "" {
+				return
+			}
+
+			var body response
+			if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Type != tc.wantType || !strings.Contains(body.Text, tc.wantText) {
+				t.Errorf("got response %+v, want type %q and text containing %q", body, tc.wantType, tc.wantText)
+			}
+		})
+	}
+
+	want := map[string]rndtest.Store{
+		"mattermost:team1:channel1": {"snacks": {"chips", "pretzels"}},
+	}
+	if got := stores.Stores(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected stores\ngot:  %v\nwant: %v", got, want)
+	}
+}
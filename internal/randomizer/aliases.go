@@ -0,0 +1,134 @@
+package randomizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (a App) saveAlias(request request) (Result, error) {
+	var (
+		ctx   = request.Context
+		alias = NormalizeGroupName(request.Operand)
+	)
+
+	aliases, ok := a.store.(AliasStore)
+	if !ok {
+		return Result{}, Error{
+			cause:    errors.New("store does not support aliases"),
+			helpText: "Whoops, aliases aren't available in this channel!",
+		}
+	}
+
+	if isForbiddenGroupName(alias) {
+		return Result{}, a.forbiddenGroupNameError(alias)
+	}
+
+	if err := a.rules.checkChars("alias", alias); err != nil {
+		return Result{}, err
+	}
+
+	if len(request.Args) != 1 {
+		return Result{}, Error{
+			cause:    fmt.Errorf("alias given %d targets", len(request.Args)),
+			helpText: "Whoops, I need the name of exactly one group for the alias to refer to!",
+		}
+	}
+
+	existing, err := a.store.Get(ctx, alias)
+	if err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble saving that alias. Please try again later!",
+		}
+	}
+	if len(existing) > 0 {
+		return Result{}, Error{
+			cause: fmt.Errorf("alias %q conflicts with existing group", alias),
+			helpText: fmt.Sprintf(
+				"Whoops, there's already a group named %q, so I can't use that name for an alias!",
+				alias,
+			),
+		}
+	}
+
+	target := NormalizeGroupName(request.Args[0])
+	group, existingGroup, err := a.lookupGroup(ctx, target)
+	if err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble saving that alias. Please try again later!",
+		}
+	}
+	if len(existingGroup.Options) == 0 {
+		return Result{}, Error{
+			cause: fmt.Errorf("alias target %q: %w", target, ErrGroupNotFound),
+			helpText: fmt.Sprintf(
+				"Whoops, I can't find the %q group in this channel.%s (Use the /save flag to create it!)",
+				target, a.suggestGroups(ctx, target),
+			),
+		}
+	}
+
+	if err := aliases.PutAlias(ctx, alias, group); err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble saving that alias. Please try again later!",
+		}
+	}
+
+	return Result{
+		resultType: SavedAlias,
+		message:    fmt.Sprintf("Done! %q is now an alias for the %q group in this channel.", alias, group),
+	}, nil
+}
+
+// lookupGroup returns the named group, following the name as an alias if the
+// store supports aliases and no group has that name. It also returns the
+// actual name of the group that it found. Like [Store.Get], it returns a Group
+// with no options and a nil error if no such group exists.
+func (a App) lookupGroup(ctx context.Context, name string) (string, Group, error) {
+	// Forbidden names never refer to real groups, and stores may use them for
+	// other purposes.
+	if isForbiddenGroupName(name) {
+		return name, Group{}, nil
+	}
+
+	group, err := a.getGroup(ctx, name)
+	if err != nil || len(group.Options) > 0 {
+		return name, group, err
+	}
+
+	aliases, ok := a.store.(AliasStore)
+	if !ok {
+		return name, Group{}, nil
+	}
+
+	target, err := aliases.GetAlias(ctx, name)
+	if err != nil || target == "" {
+		return name, Group{}, err
+	}
+
+	group, err = a.getGroup(ctx, target)
+	return target, group, err
+}
+
+// listAliasesByGroup returns a map from the names of groups to the aliases
+// that refer to them, or a nil map if the store doesn't support aliases.
+func (a App) listAliasesByGroup(ctx context.Context) (map[string][]string, error) {
+	aliases, ok := a.store.(AliasStore)
+	if !ok {
+		return nil, nil
+	}
+
+	all, err := aliases.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byGroup := make(map[string][]string)
+	for alias, group := range all {
+		byGroup[group] = append(byGroup[group], alias)
+	}
+	return byGroup, nil
+}
@@ -3,6 +3,9 @@ package randomizer
 import (
 	"context"
 	"math/rand/v2"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer/group"
 )
 
 // Store enables persistence for named groups of options.
@@ -24,19 +27,113 @@ type Store interface {
 	Delete(ctx context.Context, group string) (existed bool, err error)
 }
 
+// Group represents a saved group of options along with its metadata.
+type Group = group.Group
+
+// GroupStore is implemented by stores that can save metadata alongside the
+// options in each group. The randomizer works with all stores through the
+// [Store] methods, and uses GroupStore methods only for stores that support
+// them.
+//
+// Group metadata is optional, and its absence does not affect the existence
+// of a group. Groups saved through [Store.Put] have no metadata.
+type GroupStore interface {
+	Store
+
+	// ListGroups returns all available groups with their metadata, keyed by
+	// name. If no groups have been saved, it returns an empty map with a nil
+	// error.
+	ListGroups(ctx context.Context) (groups map[string]Group, err error)
+
+	// GetGroup returns the named group with its metadata. If the group does not
+	// exist, it returns a Group with no options and a nil error.
+	GetGroup(ctx context.Context, name string) (group Group, err error)
+
+	// PutGroup saves the provided group with its metadata, overwriting any
+	// previous group with that name.
+	PutGroup(ctx context.Context, name string, group Group) error
+}
+
+// AliasStore is implemented by stores that support aliases, or alternate names
+// that refer to existing groups. The randomizer ensures that an alias never
+// shares its name with a group.
+type AliasStore interface {
+	// ListAliases returns a map from the names of all available aliases to the
+	// names of the groups they refer to. If no aliases have been saved, it
+	// returns an empty map with a nil error.
+	ListAliases(ctx context.Context) (aliases map[string]string, err error)
+
+	// GetAlias returns the name of the group that the named alias refers to. If
+	// the alias does not exist, it returns an empty string with a nil error.
+	GetAlias(ctx context.Context, alias string) (group string, err error)
+
+	// PutAlias saves alias as an alternate name for the named group, overwriting
+	// any previous alias with that name.
+	PutAlias(ctx context.Context, alias, group string) error
+
+	// DeleteAlias ensures that the named alias no longer exists, and indicates
+	// whether the alias existed prior to this deletion attempt.
+	DeleteAlias(ctx context.Context, alias string) (existed bool, err error)
+}
+
+// AwayStore is implemented by stores that can record options that are
+// temporarily unavailable for selection from groups, like people who are on
+// vacation.
+type AwayStore interface {
+	// ListAway returns a map from the options that are away to the times at
+	// which they return. The map may include options whose return times have
+	// passed. If no options are away, it returns an empty map with a nil error.
+	ListAway(ctx context.Context) (away map[string]time.Time, err error)
+
+	// PutAway records that option is away until the provided time, overwriting
+	// any previous record for that option.
+	PutAway(ctx context.Context, option string, until time.Time) error
+
+	// DeleteAway ensures that option is no longer recorded as away, and
+	// indicates whether it was recorded prior to this deletion attempt.
+	DeleteAway(ctx context.Context, option string) (existed bool, err error)
+}
+
 // App represents a randomizer instance that can accept commands.
 type App struct {
-	name    string
-	store   Store
-	shuffle func([]string) // Overridden in tests for predictable behavior
+	name     string
+	store    Store
+	rules    Rules
+	user     string
+	expander Expander
+	shuffle  func([]string)   // Overridden in tests for predictable behavior
+	now      func() time.Time // Overridden in tests for predictable behavior
+}
+
+// Option configures optional behavior of an [App].
+type Option func(*App)
+
+// WithRules sets the rules that the app enforces when saving groups.
+func WithRules(rules Rules) Option {
+	return func(a *App) {
+		a.rules = rules
+	}
+}
+
+// WithUser identifies the user making requests to the app, in a form that the
+// frontend can display. The app records this as the creator of new groups.
+func WithUser(user string) Option {
+	return func(a *App) {
+		a.user = user
+	}
 }
 
-func NewApp(name string, store Store) App {
-	return App{
+func NewApp(name string, store Store, options ...Option) App {
+	app := App{
 		name:    name,
 		store:   store,
 		shuffle: shuffle,
+		now:     time.Now,
+	}
+	for _, option := range options {
+		option(&app)
 	}
+	return app
 }
 
 func shuffle(options []string) {
@@ -67,4 +164,10 @@ var appHandlers = map[operation]appHandler{
 	showGroup:     App.showGroup,
 	saveGroup:     App.saveGroup,
 	deleteGroup:   App.deleteGroup,
+	saveAlias:     App.saveAlias,
+	describeGroup: App.describeGroup,
+	markAway:      App.markAway,
+	listAway:      App.listAway,
+	markBack:      App.markBack,
+	editGroup:     App.editGroup,
 }
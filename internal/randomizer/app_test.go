@@ -2,7 +2,9 @@ package randomizer
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
@@ -27,9 +29,15 @@ type validator func(*testing.T, Result, error)
 // The provided store will be used to build the randomizer app instance. If an
 // expectedStore is defined, the store will be compared against it after the
 // randomizer finishes. Nil stores return an error on every operation.
+//
+// If rules are defined, the randomizer enforces them when saving groups. If
+// an expander is defined, the randomizer uses it to resolve references in
+// options; see testExpander for an example.
 var testCases = []struct {
 	description   string
 	store         rndtest.Store
+	rules         Rules
+	expander      Expander
 	args          []string
 	check         validator
 	expectedStore rndtest.Store
@@ -57,6 +65,20 @@ var testCases = []struct {
 		check:       isResult(Selection, "*one*", "*three*", "*two*"),
 	},
 
+	{
+		description: "randomizing a group with a differently cased name",
+		store:       rndtest.Store{"test": {"three", "two", "one"}},
+		args:        []string{"TeSt"},
+		check:       isResult(Selection, "*one*", "*three*", "*two*"),
+	},
+
+	{
+		description: "randomizing a group with a differently normalized name",
+		store:       rndtest.Store{"caf\u00e9": {"three", "two", "one"}},
+		args:        []string{"cafe\u0301"},
+		check:       isResult(Selection, "*one*", "*three*", "*two*"),
+	},
+
 	{
 		description: "randomizing a group that does not exist",
 		store:       rndtest.Store{},
@@ -64,6 +86,23 @@ var testCases = []struct {
 		check:       isError(`couldn't find the "test" group`),
 	},
 
+	{
+		description: "randomizing a group that does not exist with similar groups",
+		store:       rndtest.Store{"lunch": {"one", "two"}, "lurch": {"one", "two"}, "dinner": {"one", "two"}},
+		args:        []string{"lnch"},
+		check:       isError(`couldn't find the "lnch" group in this channel. Did you mean "lunch"?`),
+	},
+
+	{
+		description: "randomizing a group that does not exist with many similar groups",
+		store: rndtest.Store{
+			"frontend": {"one", "two"}, "fronted": {"one", "two"}, "frontends": {"one", "two"},
+			"front-end": {"one", "two"}, "backend": {"one", "two"},
+		},
+		args:  []string{"frontnd"},
+		check: isError(`Did you mean "fronted", "frontend", or "front-end"?`),
+	},
+
 	{
 		description: "error while getting a group",
 		store:       nil,
@@ -101,6 +140,13 @@ var testCases = []struct {
 		check:       isResult(ShowedGroup, "• one", "• three", "• two"),
 	},
 
+	{
+		description: "showing a group with a differently cased name",
+		store:       rndtest.Store{"test": {"one", "two", "three"}},
+		args:        []string{"/show", "TEST"},
+		check:       isResult(ShowedGroup, `The "test" group`, "• one", "• three", "• two"),
+	},
+
 	{
 		description: "showing a group that does not exist",
 		store:       rndtest.Store{},
@@ -108,6 +154,13 @@ var testCases = []struct {
 		check:       isError("can't find that group"),
 	},
 
+	{
+		description: "showing a group that does not exist with similar groups",
+		store:       rndtest.Store{"lunch": {"one", "two"}, "launch": {"one", "two"}},
+		args:        []string{"/show", "lanch"},
+		check:       isError(`Did you mean "launch" or "lunch"?`),
+	},
+
 	{
 		description: "unable to show a group",
 		store:       nil,
@@ -122,6 +175,34 @@ var testCases = []struct {
 		check:       isError("requires an argument"),
 	},
 
+	{
+		description: "editing a group",
+		store:       rndtest.Store{"test": {"two", "one"}},
+		args:        []string{"/edit", "Test"},
+		check:       isResult(EditingGroup, `The "test" group`, "• one", "• two"),
+	},
+
+	{
+		description: "editing a group through an alias",
+		store:       rndtest.Store{"test": {"two", "one"}, "/alias/other": {"test"}},
+		args:        []string{"/edit", "other"},
+		check:       isResult(EditingGroup, `The "test" group`, "• one", "• two"),
+	},
+
+	{
+		description: "editing a group that does not exist",
+		store:       rndtest.Store{},
+		args:        []string{"/edit", "test"},
+		check:       isError("can't find that group"),
+	},
+
+	{
+		description: "unable to edit a group",
+		store:       nil,
+		args:        []string{"/edit", "test"},
+		check:       isError("had trouble getting that group"),
+	},
+
 	{
 		description:   "saving a group",
 		store:         rndtest.Store{},
@@ -130,6 +211,14 @@ var testCases = []struct {
 		expectedStore: rndtest.Store{"test": {"one", "two"}},
 	},
 
+	{
+		description:   "saving a group with a normalized name",
+		store:         rndtest.Store{},
+		args:          []string{"/save", "Cafe\u0301", "one", "two"},
+		check:         isResult(SavedGroup, "The \"caf\u00e9\" group was saved"),
+		expectedStore: rndtest.Store{"caf\u00e9": {"one", "two"}},
+	},
+
 	{
 		description: "unable to save a group",
 		store:       nil,
@@ -144,6 +233,13 @@ var testCases = []struct {
 		check:       isError("has a special meaning"),
 	},
 
+	{
+		description: `saving a group named "HELP"`,
+		store:       rndtest.Store{},
+		args:        []string{"/save", "HELP", "one", "two"},
+		check:       isError("has a special meaning"),
+	},
+
 	{
 		description: "saving a group with a potential flag name",
 		store:       rndtest.Store{},
@@ -179,6 +275,97 @@ var testCases = []struct {
 		check:       isError("need at least two options"),
 	},
 
+	// Validating saved groups
+
+	{
+		description: "saving a group with duplicate options",
+		store:       rndtest.Store{},
+		args:        []string{"/save", "test", "one", "two", "one"},
+		check:       isError(`"one" appears more than once`),
+	},
+
+	{
+		description:   "saving a group with duplicate options removed",
+		store:         rndtest.Store{},
+		rules:         Rules{Duplicates: RemoveDuplicates},
+		args:          []string{"/save", "test", "one", "two", "one"},
+		check:         isResult(SavedGroup, "• one", "• two"),
+		expectedStore: rndtest.Store{"test": {"one", "two"}},
+	},
+
+	{
+		description: "saving a group with too few options after removing duplicates",
+		store:       rndtest.Store{},
+		rules:       Rules{Duplicates: RemoveDuplicates},
+		args:        []string{"/save", "test", "one", "one"},
+		check:       isError("need at least two options"),
+	},
+
+	{
+		description:   "saving a group with duplicate options allowed",
+		store:         rndtest.Store{},
+		rules:         Rules{Duplicates: AllowDuplicates},
+		args:          []string{"/save", "test", "one", "two", "one"},
+		check:         isResult(SavedGroup, "• one", "• one", "• two"),
+		expectedStore: rndtest.Store{"test": {"one", "one", "two"}},
+	},
+
+	{
+		description: "saving a group with too many options",
+		store:       rndtest.Store{},
+		rules:       Rules{MaxOptions: 2},
+		args:        []string{"/save", "test", "one", "two", "three"},
+		check:       isError("can't have more than 2 options"),
+	},
+
+	{
+		description: "saving a group with an option that is too long",
+		store:       rndtest.Store{},
+		rules:       Rules{MaxOptionLength: 4},
+		args:        []string{"/save", "test", "one", "three"},
+		check:       isError("can't be longer than 4 characters"),
+	},
+
+	{
+		description: "saving a group with an empty option",
+		store:       rndtest.Store{},
+		args:        []string{"/save", "test", "one", ""},
+		check:       isError("can't save an empty option"),
+	},
+
+	{
+		description: "saving a group with disallowed characters in an option",
+		store:       rndtest.Store{},
+		rules:       Rules{AllowedChars: regexp.MustCompile(`^[a-z]$`)},
+		args:        []string{"/save", "test", "one", "two!"},
+		check:       isError(`contains "!"`),
+	},
+
+	{
+		description: "saving a group with disallowed characters in its name",
+		store:       rndtest.Store{},
+		rules:       Rules{AllowedChars: regexp.MustCompile(`^[a-z]$`)},
+		args:        []string{"/save", "test-1", "one", "two"},
+		check:       isError(`contains "-"`),
+	},
+
+	{
+		description: "saving a group beyond the maximum group count",
+		store:       rndtest.Store{"first": {"one", "two"}},
+		rules:       Rules{MaxGroups: 1},
+		args:        []string{"/save", "second", "one", "two"},
+		check:       isError("maximum of 1 groups"),
+	},
+
+	{
+		description:   "overwriting a group at the maximum group count",
+		store:         rndtest.Store{"first": {"one", "two"}},
+		rules:         Rules{MaxGroups: 1},
+		args:          []string{"/save", "first", "three", "four"},
+		check:         isResult(SavedGroup, "• four", "• three"),
+		expectedStore: rndtest.Store{"first": {"four", "three"}},
+	},
+
 	{
 		description:   "deleting a group",
 		store:         rndtest.Store{"test": {"one", "two"}},
@@ -187,6 +374,14 @@ var testCases = []struct {
 		expectedStore: rndtest.Store{},
 	},
 
+	{
+		description:   "deleting a group with a differently cased name",
+		store:         rndtest.Store{"test": {"one", "two"}},
+		args:          []string{"/delete", "Test"},
+		check:         isResult(DeletedGroup, `The "test" group was deleted`),
+		expectedStore: rndtest.Store{},
+	},
+
 	{
 		description: "deleting a group that does not exist",
 		store:       rndtest.Store{},
@@ -208,6 +403,167 @@ var testCases = []struct {
 		check:       isError("requires an argument"),
 	},
 
+	// Group aliases
+
+	{
+		description:   "saving an alias",
+		store:         rndtest.Store{"frontend": {"one", "two"}},
+		args:          []string{"/alias", "FE", "frontend"},
+		check:         isResult(SavedAlias, `"fe" is now an alias for the "frontend" group`),
+		expectedStore: rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+	},
+
+	{
+		description:   "saving an alias to an alias",
+		store:         rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+		args:          []string{"/alias", "web", "fe"},
+		check:         isResult(SavedAlias, `"web" is now an alias for the "frontend" group`),
+		expectedStore: rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}, "/alias/web": {"frontend"}},
+	},
+
+	{
+		description: "saving an alias to a group that does not exist",
+		store:       rndtest.Store{"frontend": {"one", "two"}},
+		args:        []string{"/alias", "fe", "frontnd"},
+		check:       isError(`can't find the "frontnd" group in this channel. Did you mean "frontend"?`),
+	},
+
+	{
+		description: "saving an alias with the name of a group",
+		store:       rndtest.Store{"frontend": {"one", "two"}, "web": {"three", "four"}},
+		args:        []string{"/alias", "web", "frontend"},
+		check:       isError(`already a group named "web"`),
+	},
+
+	{
+		description: "saving an alias with a flag name",
+		store:       rndtest.Store{"frontend": {"one", "two"}},
+		args:        []string{"/alias", "/list", "frontend"},
+		check:       isError("has a special meaning"),
+	},
+
+	{
+		description: "saving an alias without a group",
+		store:       rndtest.Store{"frontend": {"one", "two"}},
+		args:        []string{"/alias", "fe"},
+		check:       isError("exactly one group"),
+	},
+
+	{
+		description: "unable to save an alias",
+		store:       nil,
+		args:        []string{"/alias", "fe", "frontend"},
+		check:       isError("trouble saving that alias"),
+	},
+
+	{
+		description: "saving a group with the name of an alias",
+		store:       rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+		args:        []string{"/save", "fe", "three", "four"},
+		check:       isError(`"fe" is already an alias for the "frontend" group`),
+	},
+
+	{
+		description: "randomizing an alias",
+		store:       rndtest.Store{"frontend": {"three", "two", "one"}, "/alias/fe": {"frontend"}},
+		args:        []string{"fe"},
+		check:       isResult(Selection, "*one*", "*three*", "*two*"),
+	},
+
+	{
+		description: "randomizing a reserved alias key",
+		store:       rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+		args:        []string{"/alias/fe"},
+		check:       isError(`couldn't find the "/alias/fe" group`),
+	},
+
+	{
+		description: "showing an alias",
+		store:       rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+		args:        []string{"/show", "fe"},
+		check:       isResult(ShowedGroup, `The "fe" alias refers to the "frontend" group`, "• one", "• two"),
+	},
+
+	{
+		description: "listing groups with aliases",
+		store: rndtest.Store{
+			"backend": {"one", "two"}, "frontend": {"three", "four"},
+			"/alias/web": {"frontend"}, "/alias/fe": {"frontend"},
+		},
+		args:  []string{"/list"},
+		check: isResult(ListedGroups, "• backend\n", "• frontend (also fe, web)"),
+	},
+
+	{
+		description: "suggesting an alias for a group that does not exist",
+		store:       rndtest.Store{"frontend": {"one", "two"}, "/alias/web": {"frontend"}},
+		args:        []string{"wed"},
+		check:       isError(`Did you mean "web"?`),
+	},
+
+	{
+		description:   "deleting an alias",
+		store:         rndtest.Store{"frontend": {"one", "two"}, "/alias/fe": {"frontend"}},
+		args:          []string{"/delete", "fe"},
+		check:         isResult(DeletedAlias, `The "fe" alias was deleted`, `"frontend" group it referred to still exists`),
+		expectedStore: rndtest.Store{"frontend": {"one", "two"}},
+	},
+
+	{
+		description: "deleting a group with aliases",
+		store: rndtest.Store{
+			"backend": {"one", "two"}, "frontend": {"three", "four"},
+			"/alias/web": {"frontend"}, "/alias/fe": {"frontend"}, "/alias/be": {"backend"},
+		},
+		args:          []string{"/delete", "frontend"},
+		check:         isResult(DeletedGroup, `The "frontend" group was deleted, along with its aliases "fe" and "web".`),
+		expectedStore: rndtest.Store{"backend": {"one", "two"}, "/alias/be": {"backend"}},
+	},
+
+	// Expanding references
+
+	{
+		description: "randomizing a reference",
+		expander:    testExpander,
+		args:        []string{"@team"},
+		check:       isResult(Selection, "*alice*", "*bob*", "*carol*"),
+	},
+
+	{
+		description: "randomizing references alongside options",
+		expander:    testExpander,
+		args:        []string{"@team", "dave", "@leads"},
+		check:       isResult(Selection, "*alice*", "*bob*", "*carol*", "*dave*"),
+	},
+
+	{
+		description: "randomizing a group containing a reference",
+		store:       rndtest.Store{"test": {"@leads", "dave"}},
+		expander:    testExpander,
+		args:        []string{"test"},
+		check:       isResult(Selection, "*alice*", "*dave*"),
+	},
+
+	{
+		description: "randomizing a reference without an expander",
+		args:        []string{"@team", "dave"},
+		check:       isResult(Selection, "*@team*", "*dave*"),
+	},
+
+	{
+		description: "randomizing a reference with no options",
+		expander:    testExpander,
+		args:        []string{"@empty", "dave"},
+		check:       isError("couldn't find any options to choose from in @empty"),
+	},
+
+	{
+		description: "randomizing a reference that fails to expand",
+		expander:    testExpander,
+		args:        []string{"@broken"},
+		check:       isError("trouble looking up @broken"),
+	},
+
 	// Requesting help
 
 	{
@@ -233,7 +589,7 @@ func TestMain(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
 			store := tc.store.Clone()
-			app := NewApp("randomizer", store)
+			app := NewApp("randomizer", store, WithRules(tc.rules), WithExpander(tc.expander))
 			app.shuffle = slices.Sort
 
 			res, err := app.Main(context.Background(), tc.args)
@@ -246,6 +602,34 @@ func TestMain(t *testing.T) {
 	}
 }
 
+func TestSelectionKeepsStoredOptions(t *testing.T) {
+	store := rndtest.Store{"test": {"one", "three", "two"}}
+	app := NewApp("randomizer", store)
+	app.shuffle = slices.Reverse
+
+	res, err := app.Main(context.Background(), []string{"test"})
+	isResult(Selection, "*two*", "*three*", "*one*")(t, res, err)
+	if !slices.Equal(store["test"], []string{"one", "three", "two"}) {
+		t.Errorf("selection reordered the stored group to %v", store["test"])
+	}
+}
+
+// testExpander resolves a few "@" references for test cases that need them.
+func testExpander(_ context.Context, arg string) ([]string, bool, error) {
+	switch arg {
+	case "@team":
+		return []string{"carol", "alice", "bob"}, true, nil
+	case "@leads":
+		return []string{"alice"}, true, nil
+	case "@empty":
+		return nil, true, nil
+	case "@broken":
+		return nil, true, errors.New("expander is broken")
+	default:
+		return nil, false, nil
+	}
+}
+
 func isResult(expectedType ResultType, contains ...string) validator {
 	return func(t *testing.T, res Result, err error) {
 		if err != nil {
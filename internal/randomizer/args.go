@@ -0,0 +1,36 @@
+package randomizer
+
+import (
+	"slices"
+	"strings"
+)
+
+// GroupSelectionArgs returns arguments for [App.Main] that randomize the
+// options of the named group, for frontends that accept the group name apart
+// from other input. It returns false if no group can have the name, as Main
+// would interpret the name as a flag or a request for help.
+func GroupSelectionArgs(group string) (args []string, ok bool) {
+	if isForbiddenGroupName(NormalizeGroupName(group)) {
+		return nil, false
+	}
+	return []string{group}, true
+}
+
+// OptionSelectionArgs returns arguments for [App.Main] that randomize the
+// provided options, for frontends that accept the options apart from other
+// input. It returns false if there are fewer than two options, or if every
+// option starts with a slash and Main would interpret them as a flag.
+func OptionSelectionArgs(options []string) (args []string, ok bool) {
+	// Main interprets a leading option that looks like a flag, so we lead with
+	// one that doesn't. The order of the options doesn't matter, since they're
+	// about to be shuffled.
+	i := slices.IndexFunc(options, func(option string) bool {
+		return !strings.HasPrefix(option, "/")
+	})
+	if len(options) < 2 || i < 0 {
+		return nil, false
+	}
+	args = slices.Clone(options)
+	args[0], args[i] = args[i], args[0]
+	return args, true
+}
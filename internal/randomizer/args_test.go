@@ -0,0 +1,42 @@
+package randomizer
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestGroupSelectionArgs(t *testing.T) {
+	for _, group := range []string{"help", "HELP", "/list", "/save"} {
+		if args, ok := GroupSelectionArgs(group); ok {
+			t.Errorf("GroupSelectionArgs(%q) = %v, want not ok", group, args)
+		}
+	}
+	if args, ok := GroupSelectionArgs("snacks"); !ok || !slices.Equal(args, []string{"snacks"}) {
+		t.Errorf("GroupSelectionArgs(%q) = %v, %v", "snacks", args, ok)
+	}
+}
+
+func TestOptionSelectionArgs(t *testing.T) {
+	for _, options := range [][]string{nil, {"one"}, {"/save", "/list"}} {
+		if args, ok := OptionSelectionArgs(options); ok {
+			t.Errorf("OptionSelectionArgs(%q) = %v, want not ok", options, args)
+		}
+	}
+
+	options := []string{"/save", "/help", "snacks"}
+	args, ok := OptionSelectionArgs(options)
+	if !ok {
+		t.Fatalf("OptionSelectionArgs(%q) not ok", options)
+	}
+	if !slices.Equal(options, []string{"/save", "/help", "snacks"}) {
+		t.Errorf("OptionSelectionArgs changed its input to %q", options)
+	}
+
+	app := NewApp("randomizer", nil)
+	app.shuffle = slices.Sort
+	res, err := app.Main(context.Background(), args)
+	if err != nil || res.Type() != Selection || !slices.Equal(res.Options(), []string{"/help", "/save", "snacks"}) {
+		t.Errorf("unexpected result %+v (err %v)", res, err)
+	}
+}
@@ -0,0 +1,174 @@
+package randomizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+func (a App) markAway(request request) (Result, error) {
+	var (
+		ctx    = request.Context
+		option = request.Operand
+	)
+
+	away, ok := a.store.(AwayStore)
+	if !ok {
+		return Result{}, awayUnavailableError()
+	}
+
+	if len(request.Args) != 2 || request.Args[0] != "until" {
+		return Result{}, Error{
+			cause:    fmt.Errorf("invalid away arguments %q", request.Args),
+			helpText: fmt.Sprintf(`Whoops, I need to know when %s will be back, like "/away %s until 2026-11-01"!`, option, option),
+		}
+	}
+
+	until, err := time.Parse(time.DateOnly, request.Args[1])
+	if err != nil {
+		return Result{}, Error{
+			cause: err,
+			helpText: fmt.Sprintf(
+				"Whoops, I don't understand the date %q! Please write it like 2026-11-01.", request.Args[1]),
+		}
+	}
+	now := a.now()
+	if !until.After(now) {
+		return Result{}, Error{
+			cause:    fmt.Errorf("away until %v, which is not after %v", until, now),
+			helpText: fmt.Sprintf("Whoops, %s is already in the past!", formatDate(until)),
+		}
+	}
+
+	if err := away.PutAway(ctx, option, until); err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble marking that option as away. Please try again later!",
+		}
+	}
+
+	// Records of options that have returned serve no purpose, so we take this
+	// opportunity to clean them up. As this is just housekeeping, we don't fail
+	// the request if it doesn't work.
+	if all, err := away.ListAway(ctx); err == nil {
+		for other, otherUntil := range all {
+			if !otherUntil.After(now) {
+				away.DeleteAway(ctx, other)
+			}
+		}
+	}
+
+	return Result{
+		resultType: MarkedAway,
+		message: fmt.Sprintf(
+			"Done! I'll skip %s when randomizing groups in this channel until %s.", option, formatDate(until)),
+	}, nil
+}
+
+func (a App) listAway(request request) (Result, error) {
+	current, err := a.currentAway(request.Context)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(current) == 0 {
+		return Result{
+			resultType: ListedAway,
+			message:    "Nobody is away in this channel.",
+		}, nil
+	}
+
+	options := slices.Sorted(maps.Keys(current))
+	items := make([]string, len(options))
+	for i, option := range options {
+		items[i] = fmt.Sprintf("%s (until %s)", option, formatDate(current[option]))
+	}
+
+	return Result{
+		resultType: ListedAway,
+		message:    "I'm skipping these options while they're away:\n" + bulletlist(items),
+	}, nil
+}
+
+func (a App) markBack(request request) (Result, error) {
+	var (
+		ctx    = request.Context
+		option = request.Operand
+	)
+
+	away, ok := a.store.(AwayStore)
+	if !ok {
+		return Result{}, awayUnavailableError()
+	}
+
+	existed, err := away.DeleteAway(ctx, option)
+	if err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble marking that option as back. Please try again later!",
+		}
+	}
+	if !existed {
+		return Result{}, Error{
+			cause:    fmt.Errorf("option %q not away", option),
+			helpText: fmt.Sprintf("Whoops, %s wasn't away in this channel!", option),
+		}
+	}
+
+	return Result{
+		resultType: MarkedBack,
+		message:    fmt.Sprintf("Welcome back! I'll include %s when randomizing groups in this channel.", option),
+	}, nil
+}
+
+// currentAway returns the options that are away and have not yet returned,
+// or an empty map if the store doesn't support marking options as away.
+func (a App) currentAway(ctx context.Context) (map[string]time.Time, error) {
+	away, ok := a.store.(AwayStore)
+	if !ok {
+		return nil, nil
+	}
+
+	all, err := away.ListAway(ctx)
+	if err != nil {
+		return nil, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble finding out who's away. Please try again later!",
+		}
+	}
+
+	now := a.now()
+	maps.DeleteFunc(all, func(_ string, until time.Time) bool {
+		return !until.After(now)
+	})
+	return all, nil
+}
+
+// removeAway returns the options that are not currently away, along with the
+// options that are.
+func (a App) removeAway(ctx context.Context, options []string) (present, absent []string, err error) {
+	current, err := a.currentAway(ctx)
+	if err != nil || len(current) == 0 {
+		return options, nil, err
+	}
+
+	present = make([]string, 0, len(options))
+	for _, option := range options {
+		if _, ok := current[option]; ok {
+			absent = append(absent, option)
+		} else {
+			present = append(present, option)
+		}
+	}
+	return present, absent, nil
+}
+
+func awayUnavailableError() error {
+	return Error{
+		cause:    errors.New("store does not support away options"),
+		helpText: "Whoops, marking options as away isn't available in this channel!",
+	}
+}
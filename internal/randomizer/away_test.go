@@ -0,0 +1,151 @@
+package randomizer
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestAway(t *testing.T) {
+	testCases := []struct {
+		description   string
+		store         rndtest.Store
+		args          []string
+		check         validator
+		expectedStore rndtest.Store
+	}{
+		{
+			description:   "marking an option as away",
+			store:         rndtest.Store{"/away/bob": {"2026-03-01T00:00:00Z"}},
+			args:          []string{"/away", "alice", "until", "2026-05-01"},
+			check:         isResult(MarkedAway, "I'll skip alice", "until May 1, 2026."),
+			expectedStore: rndtest.Store{"/away/alice": {"2026-05-01T00:00:00Z"}},
+		},
+
+		{
+			description: "marking an option as away without a date",
+			store:       rndtest.Store{},
+			args:        []string{"/away", "alice"},
+			check:       isError("I need to know when alice will be back"),
+		},
+
+		{
+			description: "marking an option as away with an invalid date",
+			store:       rndtest.Store{},
+			args:        []string{"/away", "alice", "until", "tomorrow"},
+			check:       isError(`I don't understand the date "tomorrow"`),
+		},
+
+		{
+			description: "marking an option as away until the past",
+			store:       rndtest.Store{},
+			args:        []string{"/away", "alice", "until", "2026-03-01"},
+			check:       isError("Mar 1, 2026 is already in the past"),
+		},
+
+		{
+			description: "unable to mark an option as away",
+			store:       nil,
+			args:        []string{"/away", "alice", "until", "2026-05-01"},
+			check:       isError("trouble marking that option as away"),
+		},
+
+		{
+			description: "listing options that are away",
+			store: rndtest.Store{
+				"/away/bob":   {"2026-05-01T00:00:00Z"},
+				"/away/alice": {"2026-06-01T00:00:00Z"},
+				"/away/carol": {"2026-03-01T00:00:00Z"},
+			},
+			args:  []string{"/away"},
+			check: isResult(ListedAway, "• alice (until Jun 1, 2026)\n• bob (until May 1, 2026)"),
+		},
+
+		{
+			description: "listing options that are away when none are",
+			store:       rndtest.Store{"/away/carol": {"2026-03-01T00:00:00Z"}},
+			args:        []string{"/away"},
+			check:       isResult(ListedAway, "Nobody is away"),
+		},
+
+		{
+			description:   "marking an option as back",
+			store:         rndtest.Store{"/away/alice": {"2026-05-01T00:00:00Z"}},
+			args:          []string{"/back", "alice"},
+			check:         isResult(MarkedBack, "I'll include alice"),
+			expectedStore: rndtest.Store{},
+		},
+
+		{
+			description: "marking an option as back that was not away",
+			store:       rndtest.Store{},
+			args:        []string{"/back", "alice"},
+			check:       isError("alice wasn't away"),
+		},
+
+		{
+			description: "randomizing a group with an option that is away",
+			store: rndtest.Store{
+				"test":        {"alice", "bob", "carol"},
+				"/away/alice": {"2026-05-01T00:00:00Z"},
+			},
+			args:  []string{"test"},
+			check: isResult(Selection, "*bob*, *carol*.", "I skipped alice, since they're away."),
+		},
+
+		{
+			description: "randomizing a group with an option that has returned",
+			store: rndtest.Store{
+				"test":        {"alice", "bob", "carol"},
+				"/away/alice": {"2026-03-01T00:00:00Z"},
+			},
+			args:  []string{"test"},
+			check: isResult(Selection, "*alice*, *bob*, *carol*."),
+		},
+
+		{
+			description: "randomizing a group with every option away",
+			store: rndtest.Store{
+				"test":        {"alice", "bob"},
+				"/away/alice": {"2026-05-01T00:00:00Z"},
+				"/away/bob":   {"2026-05-01T00:00:00Z"},
+			},
+			args:  []string{"test"},
+			check: isError(`everything in the "test" group is away`),
+		},
+
+		{
+			description: "randomizing options that are away without a group",
+			store:       rndtest.Store{"/away/alice": {"2026-05-01T00:00:00Z"}},
+			args:        []string{"alice", "bob"},
+			check:       isResult(Selection, "*alice*, *bob*."),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			store := tc.store.Clone()
+			app := NewApp("randomizer", store)
+			app.shuffle = slices.Sort
+			app.now = func() time.Time { return testNow }
+
+			res, err := app.Main(context.Background(), tc.args)
+			tc.check(t, res, err)
+
+			if tc.expectedStore != nil && !reflect.DeepEqual(store, tc.expectedStore) {
+				t.Errorf("unexpected store state\ngot:  %v\nwant: %v", store, tc.expectedStore)
+			}
+		})
+	}
+}
+
+func TestAwayWithoutStoreSupport(t *testing.T) {
+	store := struct{ Store }{rndtest.Store{}}
+	app := NewApp("randomizer", store)
+	_, err := app.Main(context.Background(), []string{"/away", "alice", "until", "2099-01-01"})
+	isError("marking options as away isn't available")(t, Result{}, err)
+}
@@ -0,0 +1,89 @@
+package randomizer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Expander resolves frontend-specific references among a user's options, like
+// mentions of a chat platform's user groups, into the options that they
+// represent.
+//
+// If arg isn't a reference that the Expander recognizes, it returns ok ==
+// false, and the randomizer treats arg as an ordinary option or group name.
+// The randomizer may modify the returned slice of options.
+type Expander func(ctx context.Context, arg string) (options []string, ok bool, err error)
+
+// WithExpander sets the Expander that the app uses to resolve references in
+// the options of a selection, including the options of saved groups.
+func WithExpander(expander Expander) Option {
+	return func(a *App) {
+		a.expander = expander
+	}
+}
+
+// expandReferences replaces every reference in options with the options that
+// it represents. If any reference is expanded, the result contains each
+// option only once, so that options represented by more than one reference
+// are no more likely to be selected than any other.
+func (a App) expandReferences(ctx context.Context, options []string) ([]string, error) {
+	if a.expander == nil {
+		return options, nil
+	}
+
+	var (
+		expanded = make([]string, 0, len(options))
+		found    bool
+	)
+	for _, option := range options {
+		expansion, ok, err := a.expandReference(ctx, option)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			expanded = append(expanded, option)
+			continue
+		}
+		found = true
+		expanded = append(expanded, expansion...)
+	}
+
+	if !found {
+		return options, nil
+	}
+
+	var (
+		seen   = make(map[string]bool, len(expanded))
+		unique = make([]string, 0, len(expanded))
+	)
+	for _, option := range expanded {
+		if !seen[option] {
+			seen[option] = true
+			unique = append(unique, option)
+		}
+	}
+	return slices.Clip(unique), nil
+}
+
+func (a App) expandReference(ctx context.Context, arg string) ([]string, bool, error) {
+	if a.expander == nil {
+		return nil, false, nil
+	}
+
+	options, ok, err := a.expander(ctx, arg)
+	if err != nil {
+		return nil, true, Error{
+			cause: unavailable(fmt.Errorf("expanding %q: %w", arg, err)),
+			helpText: fmt.Sprintf(
+				"Whoops, I had trouble looking up %s. Please try again later!", arg),
+		}
+	}
+	if ok && len(options) == 0 {
+		return nil, true, Error{
+			cause:    fmt.Errorf("%q expanded to no options", arg),
+			helpText: fmt.Sprintf("Whoops, I couldn't find any options to choose from in %s!", arg),
+		}
+	}
+	return options, ok, nil
+}
@@ -0,0 +1,23 @@
+// Package group defines the saved groups of the randomizer. It is separate
+// from the randomizer package so that rndtest can implement stores of groups
+// for the randomizer's own tests.
+package group
+
+import "time"
+
+// Group represents a saved group of options along with its metadata.
+type Group struct {
+	// Options lists the options in the group.
+	Options []string
+	// Description is a human-readable explanation of the group's purpose.
+	Description string
+	// Tags are short labels associated with the group.
+	Tags []string
+	// Creator identifies the user who first saved the group, in a form that the
+	// frontend can display.
+	Creator string
+	// Created is the time at which the group was first saved.
+	Created time.Time
+	// Updated is the time at which the group or its metadata last changed.
+	Updated time.Time
+}
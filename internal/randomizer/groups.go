@@ -1,6 +1,7 @@
 package randomizer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
@@ -12,10 +13,10 @@ func (a App) listGroups(request request) (Result, error) {
 		ctx = request.Context
 	)
 
-	groups, err := a.store.List(ctx)
+	groups, metadata, err := a.listGroupsWithMetadata(ctx)
 	if err != nil {
 		return Result{}, Error{
-			cause:    err,
+			cause:    unavailable(err),
 			helpText: "Whoops, I had trouble getting this channel's groups. Please try again later!",
 		}
 	}
@@ -27,66 +28,142 @@ func (a App) listGroups(request request) (Result, error) {
 		}, nil
 	}
 
+	aliases, err := a.listAliasesByGroup(ctx)
+	if err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble getting this channel's groups. Please try again later!",
+		}
+	}
+
 	slices.Sort(groups)
 
+	items := make([]string, len(groups))
+	for i, group := range groups {
+		items[i] = group
+		if groupAliases := aliases[group]; len(groupAliases) > 0 {
+			slices.Sort(groupAliases)
+			items[i] += fmt.Sprintf(" (also %s)", strings.Join(groupAliases, ", "))
+		}
+		if description := metadata[group].Description; description != "" {
+			items[i] += " — " + description
+		}
+	}
+
 	return Result{
 		resultType: ListedGroups,
 		message: fmt.Sprintf(
 			"The following groups are available in this channel:\n%s",
-			bulletlist(groups),
+			bulletlist(items),
 		),
+		options: groups,
 	}, nil
 }
 
 func (a App) showGroup(request request) (Result, error) {
 	var (
 		ctx  = request.Context
-		name = request.Operand
+		name = NormalizeGroupName(request.Operand)
 	)
 
-	group, err := a.store.Get(ctx, name)
+	target, group, err := a.lookupGroup(ctx, name)
 	if err != nil {
 		return Result{}, Error{
-			cause:    err,
+			cause:    unavailable(err),
 			helpText: "Whoops, I had trouble getting that group. Please try again later!",
 		}
 	}
 
-	if len(group) == 0 {
+	if len(group.Options) == 0 {
 		return Result{}, Error{
-			cause:    errors.New("group does not exist"),
-			helpText: "Whoops, I can't find that group in this channel. (Use the /save flag to create it!)",
+			cause: ErrGroupNotFound,
+			helpText: fmt.Sprintf(
+				"Whoops, I can't find that group in this channel.%s (Use the /save flag to create it!)",
+				a.suggestGroups(ctx, name),
+			),
 		}
 	}
 
-	slices.Sort(group)
+	slices.Sort(group.Options)
+
+	if target != name {
+		return Result{
+			resultType: ShowedGroup,
+			message: fmt.Sprintf(
+				"The %q alias refers to the %q group, which has the following options:\n%s%s",
+				name, target, bulletlist(group.Options), groupDetails(group),
+			),
+			groupName: target,
+			options:   group.Options,
+		}, nil
+	}
 
 	return Result{
 		resultType: ShowedGroup,
 		message: fmt.Sprintf(
-			"The %q group has the following options:\n%s",
-			name, bulletlist(group),
+			"The %q group has the following options:\n%s%s",
+			name, bulletlist(group.Options), groupDetails(group),
 		),
+		groupName: name,
+		options:   group.Options,
 	}, nil
 }
 
-func (a App) saveGroup(request request) (Result, error) {
+func (a App) editGroup(request request) (Result, error) {
 	var (
-		ctx     = request.Context
-		name    = request.Operand
-		options = request.Args
+		ctx  = request.Context
+		name = NormalizeGroupName(request.Operand)
 	)
 
-	if isForbiddenGroupName(name) {
+	// Editing an alias edits the group that it refers to, as saving under the
+	// alias's name would fail.
+	target, group, err := a.lookupGroup(ctx, name)
+	if err != nil {
 		return Result{}, Error{
-			cause: fmt.Errorf("saving with forbidden group name %q", name),
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble getting that group. Please try again later!",
+		}
+	}
+
+	if len(group.Options) == 0 {
+		return Result{}, Error{
+			cause: ErrGroupNotFound,
 			helpText: fmt.Sprintf(
-				`Whoops, %q has a special meaning and can't be used as a group name. (Type "%s help" to learn more!)`,
-				name, a.name,
+				"Whoops, I can't find that group in this channel.%s (Use the /save flag to create it!)",
+				a.suggestGroups(ctx, name),
 			),
 		}
 	}
 
+	slices.Sort(group.Options)
+
+	return Result{
+		resultType: EditingGroup,
+		message: fmt.Sprintf(
+			"The %q group has the following options:\n%s\nTo change them, use the /save flag with the full list of new options.",
+			target, bulletlist(group.Options),
+		),
+		groupName: target,
+		options:   group.Options,
+	}, nil
+}
+
+func (a App) saveGroup(request request) (Result, error) {
+	var (
+		ctx     = request.Context
+		name    = NormalizeGroupName(request.Operand)
+		options = request.Args
+	)
+
+	if isForbiddenGroupName(name) {
+		return Result{}, a.forbiddenGroupNameError(name)
+	}
+
+	options, err := a.rules.checkGroup(name, options)
+	if err != nil {
+		return Result{}, err
+	}
+
 	if len(options) < 2 {
 		return Result{}, Error{
 			cause:    errors.New("too few options to save"),
@@ -94,9 +171,41 @@ func (a App) saveGroup(request request) (Result, error) {
 		}
 	}
 
-	if err := a.store.Put(ctx, name, options); err != nil {
+	if a.rules.MaxGroups > 0 {
+		existing, err := a.store.List(ctx)
+		if err != nil {
+			return Result{}, Error{
+				cause:    unavailable(err),
+				helpText: "Whoops, I had trouble saving that group. Please try again later!",
+			}
+		}
+		if err := a.rules.checkGroupCount(name, existing); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if aliases, ok := a.store.(AliasStore); ok {
+		target, err := aliases.GetAlias(ctx, name)
+		if err != nil {
+			return Result{}, Error{
+				cause:    unavailable(err),
+				helpText: "Whoops, I had trouble saving that group. Please try again later!",
+			}
+		}
+		if target != "" {
+			return Result{}, Error{
+				cause: fmt.Errorf("group %q conflicts with existing alias", name),
+				helpText: fmt.Sprintf(
+					"Whoops, %q is already an alias for the %q group! (Use the /delete flag to remove the alias first.)",
+					name, target,
+				),
+			}
+		}
+	}
+
+	if err := a.putGroup(ctx, name, options); err != nil {
 		return Result{}, Error{
-			cause:    err,
+			cause:    unavailable(err),
 			helpText: "Whoops, I had trouble saving that group. Please try again later!",
 		}
 	}
@@ -109,38 +218,142 @@ func (a App) saveGroup(request request) (Result, error) {
 			"Done! The %q group was saved in this channel with the following options:\n%s",
 			name, bulletlist(options),
 		),
+		groupName: name,
+		options:   options,
 	}, nil
 }
 
+// putGroup saves the named group with the provided options. If the store
+// supports metadata, putGroup preserves the metadata of any existing group with
+// that name, and records the creation and update of the group.
+func (a App) putGroup(ctx context.Context, name string, options []string) error {
+	groups, ok := a.store.(GroupStore)
+	if !ok {
+		return a.store.Put(ctx, name, options)
+	}
+
+	existing, err := groups.GetGroup(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	group := existing
+	group.Options = options
+	group.Updated = a.now()
+	if len(existing.Options) == 0 {
+		group = Group{
+			Options: options,
+			Creator: a.user,
+			Created: group.Updated,
+			Updated: group.Updated,
+		}
+	}
+	return groups.PutGroup(ctx, name, group)
+}
+
 func isForbiddenGroupName(name string) bool {
 	// Keep "/" reserved as a prefix for flags. Also block "help," as it has
 	// special handling.
 	return name == "help" || strings.HasPrefix(name, "/")
 }
 
+func (a App) forbiddenGroupNameError(name string) error {
+	return Error{
+		cause: fmt.Errorf("saving with forbidden group name %q", name),
+		helpText: fmt.Sprintf(
+			`Whoops, %q has a special meaning and can't be used as a group name. (Type "%s help" to learn more!)`,
+			name, a.name,
+		),
+	}
+}
+
 func (a App) deleteGroup(request request) (Result, error) {
 	var (
 		ctx  = request.Context
-		name = request.Operand
+		name = NormalizeGroupName(request.Operand)
 	)
 
+	// Deleting an alias leaves its group intact, but deleting a group also
+	// deletes its aliases so they can't outlive it.
+	var deletedAliases []string
+	if aliases, ok := a.store.(AliasStore); ok {
+		all, err := aliases.ListAliases(ctx)
+		if err != nil {
+			return Result{}, Error{
+				cause:    unavailable(err),
+				helpText: "Whoops, I had trouble deleting that group. Please try again later!",
+			}
+		}
+
+		if target, ok := all[name]; ok {
+			return a.deleteAlias(ctx, aliases, name, target)
+		}
+
+		for alias, target := range all {
+			if target != name {
+				continue
+			}
+			if _, err := aliases.DeleteAlias(ctx, alias); err != nil {
+				return Result{}, Error{
+					cause:    unavailable(err),
+					helpText: "Whoops, I had trouble deleting that group's aliases. Please try again later!",
+				}
+			}
+			deletedAliases = append(deletedAliases, alias)
+		}
+	}
+
 	existed, err := a.store.Delete(ctx, name)
 	if err != nil {
 		return Result{}, Error{
-			cause:    err,
+			cause:    unavailable(err),
 			helpText: "Whoops, I had trouble deleting that group. Please try again later!",
 		}
 	}
 
 	if !existed {
 		return Result{}, Error{
-			cause:    errors.New("group does not exist"),
+			cause:    ErrGroupNotFound,
 			helpText: "Whoops, I can't find that group in this channel!",
 		}
 	}
 
+	if len(deletedAliases) > 0 {
+		noun := "alias"
+		if len(deletedAliases) > 1 {
+			noun = "aliases"
+		}
+		slices.Sort(deletedAliases)
+		return Result{
+			resultType: DeletedGroup,
+			message: fmt.Sprintf(
+				"Done! The %q group was deleted, along with its %s %s.",
+				name, noun, conjlist("and", quoteall(deletedAliases)),
+			),
+			groupName: name,
+		}, nil
+	}
+
 	return Result{
 		resultType: DeletedGroup,
 		message:    fmt.Sprintf("Done! The %q group was deleted.", name),
+		groupName:  name,
+	}, nil
+}
+
+func (a App) deleteAlias(ctx context.Context, aliases AliasStore, alias, group string) (Result, error) {
+	if _, err := aliases.DeleteAlias(ctx, alias); err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble deleting that alias. Please try again later!",
+		}
+	}
+
+	return Result{
+		resultType: DeletedAlias,
+		message: fmt.Sprintf(
+			"Done! The %q alias was deleted. (The %q group it referred to still exists.)",
+			alias, group,
+		),
 	}, nil
 }
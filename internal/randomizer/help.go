@@ -24,4 +24,10 @@ If you use a set of options a lot, try saving them as a *group* in the current c
 *Use a group:* {{.Name}} snacks
 *List your current channel's groups:* {{.Name}} /list
 *Show the options in a group:* {{.Name}} /show snacks
-*Delete a group:* {{.Name}} /delete snacks`
+*Edit the options in a group:* {{.Name}} /edit snacks
+*Delete a group:* {{.Name}} /delete snacks
+*Give a group another name:* {{.Name}} /alias treats snacks
+*Describe a group:* {{.Name}} /describe snacks For movie night #weekly
+*Skip an option in groups for a while:* {{.Name}} /away alice until 2026-11-01
+*See which options are being skipped:* {{.Name}} /away
+*Stop skipping an option:* {{.Name}} /back alice`
@@ -1,6 +1,9 @@
 package randomizer
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func inlinelist(items []string) string {
 	var b strings.Builder
@@ -26,3 +29,26 @@ func bulletlist(items []string) string {
 	}
 	return b.String()
 }
+
+// conjlist joins items into an English list using the provided conjunction,
+// e.g. "one, two, and three."
+func conjlist(conj string, items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " " + conj + " " + items[1]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + ", " + conj + " " + items[len(items)-1]
+	}
+}
+
+func quoteall(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return quoted
+}
@@ -0,0 +1,147 @@
+package randomizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (a App) describeGroup(request request) (Result, error) {
+	var (
+		ctx         = request.Context
+		name        = NormalizeGroupName(request.Operand)
+		description = strings.Join(request.Args, " ")
+	)
+
+	groups, ok := a.store.(GroupStore)
+	if !ok {
+		return Result{}, Error{
+			cause:    errors.New("store does not support group metadata"),
+			helpText: "Whoops, group descriptions aren't available in this channel!",
+		}
+	}
+
+	target, group, err := a.lookupGroup(ctx, name)
+	if err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble describing that group. Please try again later!",
+		}
+	}
+
+	if len(group.Options) == 0 {
+		return Result{}, Error{
+			cause: ErrGroupNotFound,
+			helpText: fmt.Sprintf(
+				"Whoops, I can't find that group in this channel.%s (Use the /save flag to create it!)",
+				a.suggestGroups(ctx, name),
+			),
+		}
+	}
+
+	group.Description = description
+	group.Tags = parseTags(request.Args)
+	group.Updated = a.now()
+	if err := groups.PutGroup(ctx, target, group); err != nil {
+		return Result{}, Error{
+			cause:    unavailable(err),
+			helpText: "Whoops, I had trouble describing that group. Please try again later!",
+		}
+	}
+
+	if description == "" {
+		return Result{
+			resultType: DescribedGroup,
+			message:    fmt.Sprintf("Done! The %q group no longer has a description.", target),
+		}, nil
+	}
+
+	return Result{
+		resultType: DescribedGroup,
+		message:    fmt.Sprintf("Done! The %q group now has the description: %s", target, description),
+	}, nil
+}
+
+// parseTags returns the sorted, unique tags among the words of a description,
+// where tags are words that start with "#".
+func parseTags(words []string) []string {
+	var tags []string
+	for _, word := range words {
+		if tag, ok := strings.CutPrefix(word, "#"); ok && tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// getGroup returns the named group, including its metadata if the store
+// supports it. Like [Store.Get], it returns a Group with no options and a nil
+// error if the group does not exist.
+func (a App) getGroup(ctx context.Context, name string) (Group, error) {
+	if groups, ok := a.store.(GroupStore); ok {
+		return groups.GetGroup(ctx, name)
+	}
+	options, err := a.store.Get(ctx, name)
+	return Group{Options: options}, err
+}
+
+// listGroupsWithMetadata returns the names of all groups, along with their
+// metadata if the store supports it.
+func (a App) listGroupsWithMetadata(ctx context.Context) ([]string, map[string]Group, error) {
+	if groups, ok := a.store.(GroupStore); ok {
+		all, err := groups.ListGroups(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		names := make([]string, 0, len(all))
+		for name := range all {
+			names = append(names, name)
+		}
+		return names, all, nil
+	}
+
+	names, err := a.store.List(ctx)
+	return names, nil, err
+}
+
+// groupDetails returns a description of a group's metadata, with a leading
+// newline to simplify concatenation into a message. If the group has no
+// metadata, it returns an empty string.
+func groupDetails(group Group) string {
+	var b strings.Builder
+
+	if group.Description != "" {
+		fmt.Fprintf(&b, "\n*Description:* %s", group.Description)
+	}
+	if len(group.Tags) > 0 {
+		fmt.Fprintf(&b, "\n*Tags:* %s", strings.Join(group.Tags, ", "))
+	}
+
+	var history []string
+	switch {
+	case group.Creator != "" && !group.Created.IsZero():
+		history = append(history, fmt.Sprintf("Created by %s on %s.", group.Creator, formatDate(group.Created)))
+	case group.Creator != "":
+		history = append(history, fmt.Sprintf("Created by %s.", group.Creator))
+	case !group.Created.IsZero():
+		history = append(history, fmt.Sprintf("Created on %s.", formatDate(group.Created)))
+	}
+	if !group.Updated.IsZero() && !group.Updated.Equal(group.Created) {
+		history = append(history, fmt.Sprintf("Last updated on %s.", formatDate(group.Updated)))
+	}
+	if len(history) > 0 {
+		fmt.Fprintf(&b, "\n_%s_", strings.Join(history, " "))
+	}
+
+	return b.String()
+}
+
+const dateFormat = "Jan 2, 2006"
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format(dateFormat)
+}
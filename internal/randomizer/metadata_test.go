@@ -0,0 +1,154 @@
+package randomizer
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+var (
+	testCreated = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testNow     = time.Date(2026, time.April, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestGroupMetadata(t *testing.T) {
+	testCases := []struct {
+		description      string
+		store            rndtest.Store
+		metadata         map[string]Group
+		args             []string
+		check            validator
+		expectedMetadata map[string]Group
+	}{
+		{
+			description: "saving a new group",
+			store:       rndtest.Store{},
+			metadata:    map[string]Group{},
+			args:        []string{"/save", "test", "one", "two"},
+			check:       isResult(SavedGroup, `The "test" group was saved`),
+			expectedMetadata: map[string]Group{
+				"test": {Creator: "<@alice>", Created: testNow, Updated: testNow},
+			},
+		},
+
+		{
+			description: "saving over an existing group",
+			store:       rndtest.Store{"test": {"one", "two"}},
+			metadata: map[string]Group{
+				"test": {Description: "Testing", Tags: []string{"tag"}, Creator: "<@bob>", Created: testCreated, Updated: testCreated},
+			},
+			args:  []string{"/save", "test", "three", "four"},
+			check: isResult(SavedGroup, `The "test" group was saved`),
+			expectedMetadata: map[string]Group{
+				"test": {Description: "Testing", Tags: []string{"tag"}, Creator: "<@bob>", Created: testCreated, Updated: testNow},
+			},
+		},
+
+		{
+			description: "describing a group",
+			store:       rndtest.Store{"test": {"one", "two"}},
+			metadata: map[string]Group{
+				"test": {Creator: "<@bob>", Created: testCreated, Updated: testCreated},
+			},
+			args:  []string{"/describe", "Test", "For", "#testing", "and", "#demos", "#testing"},
+			check: isResult(DescribedGroup, `The "test" group now has the description: For #testing and #demos #testing`),
+			expectedMetadata: map[string]Group{
+				"test": {
+					Description: "For #testing and #demos #testing", Tags: []string{"demos", "testing"},
+					Creator: "<@bob>", Created: testCreated, Updated: testNow,
+				},
+			},
+		},
+
+		{
+			description: "describing a group through an alias",
+			store:       rndtest.Store{"test": {"one", "two"}, "/alias/t": {"test"}},
+			metadata:    map[string]Group{},
+			args:        []string{"/describe", "t", "Testing"},
+			check:       isResult(DescribedGroup, `The "test" group now has the description: Testing`),
+			expectedMetadata: map[string]Group{
+				"test": {Description: "Testing", Updated: testNow},
+			},
+		},
+
+		{
+			description: "removing the description of a group",
+			store:       rndtest.Store{"test": {"one", "two"}},
+			metadata: map[string]Group{
+				"test": {Description: "Testing", Tags: []string{"tag"}},
+			},
+			args:  []string{"/describe", "test"},
+			check: isResult(DescribedGroup, `The "test" group no longer has a description`),
+			expectedMetadata: map[string]Group{
+				"test": {Updated: testNow},
+			},
+		},
+
+		{
+			description: "describing a group that does not exist",
+			store:       rndtest.Store{"test": {"one", "two"}},
+			metadata:    map[string]Group{},
+			args:        []string{"/describe", "tst", "Testing"},
+			check:       isError(`can't find that group in this channel. Did you mean "test"?`),
+		},
+
+		{
+			description: "unable to describe a group",
+			store:       nil,
+			metadata:    map[string]Group{},
+			args:        []string{"/describe", "test", "Testing"},
+			check:       isError("trouble describing that group"),
+		},
+
+		{
+			description: "showing a group with metadata",
+			store:       rndtest.Store{"test": {"one", "two"}},
+			metadata: map[string]Group{
+				"test": {Description: "For #testing", Tags: []string{"testing"}, Creator: "<@bob>", Created: testCreated, Updated: testNow},
+			},
+			args: []string{"/show", "test"},
+			check: isResult(ShowedGroup,
+				"• one\n• two\n",
+				"*Description:* For #testing\n",
+				"*Tags:* testing\n",
+				"_Created by <@bob> on Mar 1, 2026. Last updated on Apr 1, 2026._",
+			),
+		},
+
+		{
+			description: "listing groups with descriptions",
+			store:       rndtest.Store{"first": {"one", "two"}, "second": {"three", "four"}, "/alias/2": {"second"}},
+			metadata: map[string]Group{
+				"second": {Description: "The second group"},
+			},
+			args:  []string{"/list"},
+			check: isResult(ListedGroups, "• first\n", "• second (also 2) — The second group"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			store := rndtest.GroupStore{Store: tc.store.Clone(), Metadata: tc.metadata}
+			app := NewApp("randomizer", store, WithUser("<@alice>"))
+			app.shuffle = slices.Sort
+			app.now = func() time.Time { return testNow }
+
+			res, err := app.Main(context.Background(), tc.args)
+			tc.check(t, res, err)
+
+			if tc.expectedMetadata != nil && !reflect.DeepEqual(store.Metadata, tc.expectedMetadata) {
+				t.Errorf("unexpected metadata\ngot:  %+v\nwant: %+v", store.Metadata, tc.expectedMetadata)
+			}
+		})
+	}
+}
+
+func TestDescribeWithoutMetadataSupport(t *testing.T) {
+	app := NewApp("randomizer", rndtest.Store{"test": {"one", "two"}})
+	_, err := app.Main(context.Background(), []string{"/describe", "test", "Testing"})
+	isError("descriptions aren't available")(t, Result{}, err)
+}
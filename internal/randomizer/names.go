@@ -0,0 +1,15 @@
+package randomizer
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeGroupName returns the canonical form of a group name, under which
+// the randomizer saves and looks up the group. Canonical names are case folded
+// and in Unicode Normalization Form C, so that names differing only in case or
+// in the encoding of accented characters refer to the same group.
+func NormalizeGroupName(name string) string {
+	// Case folding can produce unnormalized output, so we normalize last.
+	return norm.NFC.String(cases.Fold().String(name))
+}
@@ -2,7 +2,10 @@
 // suitable for use by multiple frontends.
 package randomizer
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // ResultType represents the type of successful result returned by the
 // randomizer.
@@ -22,12 +25,50 @@ const (
 	SavedGroup
 	// DeletedGroup indicates that a group was successfully deleted.
 	DeletedGroup
+	// SavedAlias indicates that an alias for a group was successfully saved.
+	SavedAlias
+	// DeletedAlias indicates that an alias for a group was successfully deleted.
+	DeletedAlias
+	// DescribedGroup indicates that the description of a group was successfully
+	// changed.
+	DescribedGroup
+	// MarkedAway indicates that an option was successfully marked as away.
+	MarkedAway
+	// ListedAway indicates that the list of options that are away was
+	// successfully obtained.
+	ListedAway
+	// MarkedBack indicates that an option is no longer marked as away.
+	MarkedBack
+	// EditingGroup indicates that the options of a single group were obtained
+	// for editing. Frontends that can present an editor should do so, using
+	// [Result.GroupName] and [Result.Options], and save the changes with the
+	// /save flag. Others can show the message, which lists the options.
+	EditingGroup
 )
 
+// IsPublic reports whether a result of this type concerns everyone in the
+// channel where the randomizer ran, like a selection or a change to a group,
+// rather than only the user who ran it. Frontends that can show results to a
+// single user should do so for the other types.
+func (t ResultType) IsPublic() bool {
+	switch t {
+	case Selection,
+		SavedGroup, DeletedGroup,
+		SavedAlias, DeletedAlias,
+		DescribedGroup,
+		MarkedAway, MarkedBack:
+		return true
+	default:
+		return false
+	}
+}
+
 // Result represents a successful randomizer operation.
 type Result struct {
 	resultType ResultType
 	message    string
+	groupName  string
+	options    []string
 }
 
 // Type returns the type of this result.
@@ -40,6 +81,23 @@ func (r Result) Message() string {
 	return r.message
 }
 
+// GroupName returns the name of the group that a result refers to, for
+// results of type ShowedGroup, SavedGroup, DeletedGroup, and EditingGroup. When
+// a ShowedGroup or EditingGroup result is reached through an alias, GroupName
+// returns the name of the group rather than the alias.
+func (r Result) GroupName() string {
+	return r.groupName
+}
+
+// Options returns the options of the group that a result refers to, for
+// results of type ShowedGroup, SavedGroup, and EditingGroup. For a Selection
+// result, Options returns the randomized options in their selected order, and
+// for a ListedGroups result, it returns the names of the groups in sorted
+// order.
+func (r Result) Options() []string {
+	return r.options
+}
+
 // Error represents an error encountered by the randomizer. It includes
 // friendly help messages that can be displayed directly to users when errors
 // occur, along with an underlying developer-friendly error that may be useful
@@ -59,6 +117,12 @@ func (e Error) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns the underlying cause of this error, so that [errors.Is] and
+// [errors.As] can inspect it.
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
 // HelpText returns user-friendly help text associated with this error. While
 // the underlying error is more suitable for developer use, the help text may
 // be displayed directly to a user.
@@ -69,3 +133,33 @@ func (e Error) HelpText() string {
 
 	return fmt.Sprintf("Whoops, I had a problem… %v.", e.cause)
 }
+
+var (
+	// ErrGroupNotFound is the cause of errors that the randomizer returns when a
+	// request refers to a group that does not exist. It may be wrapped, and is
+	// best checked with [errors.Is].
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrUnavailable matches the causes of errors that the randomizer returns
+	// when its store or expander fails, such that retrying the request later
+	// might succeed. It is best checked with [errors.Is].
+	ErrUnavailable = errors.New("randomizer temporarily unavailable")
+)
+
+// unavailableError marks an error as matching ErrUnavailable, without changing
+// its message.
+type unavailableError struct {
+	error
+}
+
+func unavailable(err error) error {
+	return unavailableError{err}
+}
+
+func (e unavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+func (e unavailableError) Unwrap() error {
+	return e.error
+}
@@ -1,8 +1,12 @@
 package randomizer
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
 )
 
 var errOriginalCause = errors.New("there was a test error")
@@ -46,3 +50,62 @@ func TestErrorWithoutHelpText(t *testing.T) {
 		t.Errorf("got help text %q, want %q", err.HelpText(), expectedHelpText)
 	}
 }
+
+func TestErrorIs(t *testing.T) {
+	testCases := []struct {
+		description string
+		store       rndtest.Store
+		args        []string
+		want        error
+	}{
+		{"showing a missing group", rndtest.Store{}, []string{"/show", "nope"}, ErrGroupNotFound},
+		{"selecting from a missing group", rndtest.Store{}, []string{"nope"}, ErrGroupNotFound},
+		{"deleting a missing group", rndtest.Store{}, []string{"/delete", "nope"}, ErrGroupNotFound},
+		{"store failure", nil, []string{"/show", "nope"}, ErrUnavailable},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := NewApp("randomizer", tc.store).Main(context.Background(), tc.args)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("got error %v, want one matching %v", err, tc.want)
+			}
+		})
+	}
+
+	// The store's own error message should pass through unchanged.
+	_, err := NewApp("randomizer", rndtest.Store(nil)).Main(context.Background(), []string{"/list"})
+	if err == nil || err.Error() != "store list error" {
+		t.Errorf("got error %v, want the store's error", err)
+	}
+}
+
+func TestResultDetails(t *testing.T) {
+	store := rndtest.Store{"test": {"two", "one"}}
+	app := NewApp("randomizer", store)
+	app.shuffle = slices.Sort
+
+	res, err := app.Main(context.Background(), []string{"/save", "new", "b", "a"})
+	if err != nil || res.GroupName() != "new" || !slices.Equal(res.Options(), []string{"a", "b"}) {
+		t.Errorf("unexpected save result %+v (err %v)", res, err)
+	}
+
+	res, err = app.Main(context.Background(), []string{"test"})
+	if err != nil || !slices.Equal(res.Options(), []string{"one", "two"}) {
+		t.Errorf("unexpected selection result %+v (err %v)", res, err)
+	}
+	if !slices.Equal(store["test"], []string{"two", "one"}) {
+		t.Errorf("selection changed the stored group to %v", store["test"])
+	}
+}
+
+func TestResultTypeIsPublic(t *testing.T) {
+	public := []ResultType{
+		Selection, SavedGroup, DeletedGroup, SavedAlias, DeletedAlias,
+		DescribedGroup, MarkedAway, MarkedBack,
+	}
+	for rtype := Selection; rtype <= EditingGroup; rtype++ {
+		if got, want := rtype.IsPublic(), slices.Contains(public, rtype); got != want {
+			t.Errorf("ResultType(%d).IsPublic() = %v, want %v", rtype, got, want)
+		}
+	}
+}
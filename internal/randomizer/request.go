@@ -14,6 +14,12 @@ const (
 	showGroup
 	saveGroup
 	deleteGroup
+	saveAlias
+	describeGroup
+	markAway
+	listAway
+	markBack
+	editGroup
 )
 
 // request represents a single user request to a randomizer instance, created
@@ -52,6 +58,16 @@ func parseArgs(args []string) (op operation, operand string, opargs []string, er
 	case "/list":
 		return listGroups, "", args, nil
 
+	// ...as does listing the options that are away, though changing whether an
+	// option is away requires the option as an operand...
+	case "/away":
+		if len(args) == 1 {
+			return listAway, "", args, nil
+		}
+		op = markAway
+	case "/back":
+		op = markBack
+
 	// ...and everything else needs the name of a group to operate on, which we
 	// validate and extract out from the rest of the arguments for convenience. We
 	// make no assumptions about how each operation uses the rest of the available
@@ -60,8 +76,14 @@ func parseArgs(args []string) (op operation, operand string, opargs []string, er
 		op = showGroup
 	case "/save":
 		op = saveGroup
+	case "/edit":
+		op = editGroup
 	case "/delete":
 		op = deleteGroup
+	case "/alias":
+		op = saveAlias
+	case "/describe":
+		op = describeGroup
 	}
 
 	if len(args) < 2 {
@@ -0,0 +1,131 @@
+package rndtest
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/featherbread/randomizer/internal/randomizer/group"
+)
+
+// GroupStore extends Store to implement randomizer.GroupStore, by holding the
+// metadata of each group in a map separate from its options. As with Store, a
+// GroupStore with a nil Store returns errors for every operation.
+type GroupStore struct {
+	Store
+	Metadata map[string]group.Group
+}
+
+// NewGroupStore returns an empty GroupStore.
+func NewGroupStore() GroupStore {
+	return GroupStore{Store: make(Store), Metadata: make(map[string]group.Group)}
+}
+
+// ListGroups implements randomizer.GroupStore.
+func (s GroupStore) ListGroups(ctx context.Context) (map[string]group.Group, error) {
+	names, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]group.Group, len(names))
+	for _, name := range names {
+		groups[name], _ = s.GetGroup(ctx, name)
+	}
+	return groups, nil
+}
+
+// GetGroup implements randomizer.GroupStore.
+func (s GroupStore) GetGroup(ctx context.Context, name string) (group.Group, error) {
+	options, err := s.Get(ctx, name)
+	if err != nil || len(options) == 0 {
+		return group.Group{}, err
+	}
+	g := s.Metadata[name]
+	g.Options = options
+	return g, nil
+}
+
+// PutGroup implements randomizer.GroupStore.
+func (s GroupStore) PutGroup(ctx context.Context, name string, g group.Group) error {
+	if err := s.Put(ctx, name, g.Options); err != nil {
+		return err
+	}
+	g.Options = nil
+	s.Metadata[name] = g
+	return nil
+}
+
+// Delete implements randomizer.Store, and removes the group's metadata along
+// with its options.
+func (s GroupStore) Delete(ctx context.Context, name string) (existed bool, err error) {
+	existed, err = s.Store.Delete(ctx, name)
+	if err == nil {
+		delete(s.Metadata, name)
+	}
+	return
+}
+
+// Partitions provides a separate GroupStore for each partition of a database.
+// The zero value has no partitions, and is ready to use.
+type Partitions struct {
+	mu     sync.Mutex
+	stores map[string]PartitionStore
+}
+
+// PartitionStore is the GroupStore for one of the Partitions, which can also
+// list every partition with saved data like the stores of real databases.
+type PartitionStore struct {
+	GroupStore
+	partitions *Partitions
+}
+
+// Store returns the store for the named partition, creating it if necessary.
+func (p *Partitions) Store(partition string) PartitionStore {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stores == nil {
+		p.stores = make(map[string]PartitionStore)
+	}
+	if _, ok := p.stores[partition]; !ok {
+		p.stores[partition] = PartitionStore{NewGroupStore(), p}
+	}
+	return p.stores[partition]
+}
+
+// Break makes the store for the named partition return errors for every
+// operation, like a GroupStore with a nil Store.
+func (p *Partitions) Break(partition string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stores == nil {
+		p.stores = make(map[string]PartitionStore)
+	}
+	p.stores[partition] = PartitionStore{partitions: p}
+}
+
+// Stores returns the Store holding the groups of each partition with a store,
+// keyed by partition.
+func (p *Partitions) Stores() map[string]Store {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stores := make(map[string]Store, len(p.stores))
+	for partition, store := range p.stores {
+		stores[partition] = store.Store
+	}
+	return stores
+}
+
+// ListPartitions returns the sorted names of the partitions whose stores hold
+// any data, including data other than groups.
+func (s PartitionStore) ListPartitions(_ context.Context) ([]string, error) {
+	s.partitions.mu.Lock()
+	defer s.partitions.mu.Unlock()
+	var partitions []string
+	for _, partition := range slices.Sorted(maps.Keys(s.partitions.stores)) {
+		if len(s.partitions.stores[partition].Store) > 0 {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions, nil
+}
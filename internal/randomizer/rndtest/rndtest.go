@@ -4,14 +4,37 @@ package rndtest
 import (
 	"context"
 	"errors"
-	"maps"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Store implements randomizer.Store by mapping group names to sorted lists of
 // strings. A nil Store returns errors for every operation.
+//
+// Store also implements randomizer.AliasStore, by mapping keys of the form
+// "/alias/NAME" to single-element lists containing the name of the group that
+// the alias refers to. Group names can't conflict with these keys, as the
+// randomizer reserves the "/" prefix for flags.
+//
+// Store also implements randomizer.AwayStore, by mapping keys of the form
+// "/away/OPTION" to single-element lists containing the RFC 3339 time at which
+// the option returns.
+//
+// Store also implements slack.BotTokenStore, by mapping the "/bottoken" key to
+// a single-element list containing the token, and slack.RateLimitStore, by
+// mapping keys of the form "/ratelimit/KEY" to two-element lists containing
+// the number of tokens and the RFC 3339 time at which they were counted.
 type Store map[string][]string
 
+const (
+	aliasPrefix     = "/alias/"
+	awayPrefix      = "/away/"
+	rateLimitPrefix = "/ratelimit/"
+	botTokenKey     = "/bottoken"
+)
+
 // Clone returns a deep copy of the original store.
 func (s Store) Clone() Store {
 	if s == nil {
@@ -29,7 +52,14 @@ func (s Store) List(_ context.Context) ([]string, error) {
 	if s == nil {
 		return nil, errors.New("store list error")
 	}
-	return slices.Sorted(maps.Keys(s)), nil
+	var groups []string
+	for name := range s {
+		if !strings.HasPrefix(name, "/") {
+			groups = append(groups, name)
+		}
+	}
+	slices.Sort(groups)
+	return groups, nil
 }
 
 // Get implements randomizer.Store.
@@ -60,3 +90,144 @@ func (s Store) Delete(_ context.Context, name string) (existed bool, err error)
 	delete(s, name)
 	return
 }
+
+// ListAliases implements randomizer.AliasStore.
+func (s Store) ListAliases(_ context.Context) (map[string]string, error) {
+	if s == nil {
+		return nil, errors.New("store list aliases error")
+	}
+	aliases := make(map[string]string)
+	for name, value := range s {
+		if alias, ok := strings.CutPrefix(name, aliasPrefix); ok {
+			aliases[alias] = value[0]
+		}
+	}
+	return aliases, nil
+}
+
+// GetAlias implements randomizer.AliasStore.
+func (s Store) GetAlias(_ context.Context, alias string) (string, error) {
+	if s == nil {
+		return "", errors.New("store get alias error")
+	}
+	if value, ok := s[aliasPrefix+alias]; ok {
+		return value[0], nil
+	}
+	return "", nil
+}
+
+// PutAlias implements randomizer.AliasStore.
+func (s Store) PutAlias(_ context.Context, alias, group string) error {
+	if s == nil {
+		return errors.New("store put alias error")
+	}
+	s[aliasPrefix+alias] = []string{group}
+	return nil
+}
+
+// DeleteAlias implements randomizer.AliasStore.
+func (s Store) DeleteAlias(_ context.Context, alias string) (existed bool, err error) {
+	if s == nil {
+		return false, errors.New("store delete alias error")
+	}
+	_, existed = s[aliasPrefix+alias]
+	delete(s, aliasPrefix+alias)
+	return
+}
+
+// ListAway implements randomizer.AwayStore.
+func (s Store) ListAway(_ context.Context) (map[string]time.Time, error) {
+	if s == nil {
+		return nil, errors.New("store list away error")
+	}
+	away := make(map[string]time.Time)
+	for name, value := range s {
+		if option, ok := strings.CutPrefix(name, awayPrefix); ok {
+			until, err := time.Parse(time.RFC3339, value[0])
+			if err != nil {
+				return nil, err
+			}
+			away[option] = until
+		}
+	}
+	return away, nil
+}
+
+// PutAway implements randomizer.AwayStore.
+func (s Store) PutAway(_ context.Context, option string, until time.Time) error {
+	if s == nil {
+		return errors.New("store put away error")
+	}
+	s[awayPrefix+option] = []string{until.UTC().Format(time.RFC3339)}
+	return nil
+}
+
+// DeleteAway implements randomizer.AwayStore.
+func (s Store) DeleteAway(_ context.Context, option string) (existed bool, err error) {
+	if s == nil {
+		return false, errors.New("store delete away error")
+	}
+	_, existed = s[awayPrefix+option]
+	delete(s, awayPrefix+option)
+	return
+}
+
+// GetBotToken implements slack.BotTokenStore.
+func (s Store) GetBotToken(_ context.Context) (string, error) {
+	if s == nil {
+		return "", errors.New("store get bot token error")
+	}
+	if value, ok := s[botTokenKey]; ok {
+		return value[0], nil
+	}
+	return "", nil
+}
+
+// PutBotToken implements slack.BotTokenStore.
+func (s Store) PutBotToken(_ context.Context, token string) error {
+	if s == nil {
+		return errors.New("store put bot token error")
+	}
+	s[botTokenKey] = []string{token}
+	return nil
+}
+
+// DeleteBotToken implements slack.BotTokenStore.
+func (s Store) DeleteBotToken(_ context.Context) (existed bool, err error) {
+	if s == nil {
+		return false, errors.New("store delete bot token error")
+	}
+	_, existed = s[botTokenKey]
+	delete(s, botTokenKey)
+	return
+}
+
+// GetRateLimit implements slack.RateLimitStore.
+func (s Store) GetRateLimit(_ context.Context, key string) (float64, time.Time, error) {
+	if s == nil {
+		return 0, time.Time{}, errors.New("store get rate limit error")
+	}
+	value, ok := s[rateLimitPrefix+key]
+	if !ok {
+		return 0, time.Time{}, nil
+	}
+	tokens, err := strconv.ParseFloat(value[0], 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	at, err := time.Parse(time.RFC3339Nano, value[1])
+	return tokens, at, err
+}
+
+// PutRateLimit implements slack.RateLimitStore.
+func (s Store) PutRateLimit(ctx context.Context, key string, tokens float64, at, prevAt, _ time.Time) (bool, error) {
+	if s == nil {
+		return false, errors.New("store put rate limit error")
+	}
+	_, currentAt, err := s.GetRateLimit(ctx, key)
+	if err != nil || !currentAt.Equal(prevAt) {
+		return false, err
+	}
+	s[rateLimitPrefix+key] = []string{strconv.FormatFloat(tokens, 'g', -1, 64), at.UTC().Format(time.RFC3339Nano)}
+	return true, nil
+}
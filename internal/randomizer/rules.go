@@ -0,0 +1,200 @@
+package randomizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Rules sets operator-configured limits on the groups that users can save.
+//
+// The zero value of Rules places no limits on the size of groups, and rejects
+// groups with duplicate options, since some store backends can't represent
+// them.
+type Rules struct {
+	// MaxOptions limits the number of options in a single group.
+	// Zero means there is no limit.
+	MaxOptions int
+	// MaxOptionLength limits the length of each option, in characters.
+	// Zero means there is no limit.
+	MaxOptionLength int
+	// MaxGroups limits the number of groups saved in a single partition.
+	// Zero means there is no limit.
+	MaxGroups int
+	// Duplicates controls how the randomizer handles groups with repeated
+	// options.
+	Duplicates DuplicatePolicy
+	// AllowedChars, if non-nil, must match every individual character of a group
+	// name or option.
+	AllowedChars *regexp.Regexp
+}
+
+// DuplicatePolicy represents the handling of repeated options when saving a
+// group.
+type DuplicatePolicy int
+
+const (
+	// RejectDuplicates refuses to save groups with repeated options.
+	RejectDuplicates DuplicatePolicy = iota
+	// RemoveDuplicates silently saves only the first instance of each option.
+	RemoveDuplicates
+	// AllowDuplicates saves repeated options as given. Note that the DynamoDB
+	// store backend does not support this.
+	AllowDuplicates
+)
+
+var duplicatePolicyNames = map[string]DuplicatePolicy{
+	"reject": RejectDuplicates,
+	"remove": RemoveDuplicates,
+	"allow":  AllowDuplicates,
+}
+
+// RulesFromEnv returns Rules based on available environment variables.
+//
+// RANDOMIZER_MAX_OPTIONS, RANDOMIZER_MAX_OPTION_LENGTH, and
+// RANDOMIZER_MAX_GROUPS set the corresponding numeric limits.
+//
+// RANDOMIZER_DUPLICATE_OPTIONS may be "reject" (the default), "remove", or
+// "allow".
+//
+// RANDOMIZER_ALLOWED_CHARS, if set, is the body of a regular expression
+// character class (like `\p{L}\p{N}_.-`) matching the characters allowed in
+// group names and options.
+func RulesFromEnv() (rules Rules, err error) {
+	limits := []struct {
+		key   string
+		value *int
+	}{
+		{"RANDOMIZER_MAX_OPTIONS", &rules.MaxOptions},
+		{"RANDOMIZER_MAX_OPTION_LENGTH", &rules.MaxOptionLength},
+		{"RANDOMIZER_MAX_GROUPS", &rules.MaxGroups},
+	}
+	for _, limit := range limits {
+		env, ok := os.LookupEnv(limit.key)
+		if !ok {
+			continue
+		}
+		*limit.value, err = strconv.Atoi(env)
+		if err != nil || *limit.value < 0 {
+			return Rules{}, fmt.Errorf("%s is not a valid non-negative integer: %q", limit.key, env)
+		}
+	}
+
+	if env, ok := os.LookupEnv("RANDOMIZER_DUPLICATE_OPTIONS"); ok {
+		policy, ok := duplicatePolicyNames[env]
+		if !ok {
+			return Rules{}, fmt.Errorf("RANDOMIZER_DUPLICATE_OPTIONS must be reject, remove, or allow, not %q", env)
+		}
+		rules.Duplicates = policy
+	}
+
+	if env, ok := os.LookupEnv("RANDOMIZER_ALLOWED_CHARS"); ok {
+		rules.AllowedChars, err = regexp.Compile(`^[` + env + `]$`)
+		if err != nil {
+			return Rules{}, fmt.Errorf("RANDOMIZER_ALLOWED_CHARS is not a valid character class: %w", err)
+		}
+	}
+
+	return rules, nil
+}
+
+// checkGroup validates a group that a user wishes to save, and returns the
+// options that should actually be saved.
+func (r Rules) checkGroup(name string, options []string) ([]string, error) {
+	if err := r.checkChars("group name", name); err != nil {
+		return nil, err
+	}
+
+	if r.MaxOptions > 0 && len(options) > r.MaxOptions {
+		return nil, Error{
+			cause: fmt.Errorf("group has %d options, more than limit of %d", len(options), r.MaxOptions),
+			helpText: fmt.Sprintf(
+				"Whoops, groups can't have more than %d options!", r.MaxOptions),
+		}
+	}
+
+	for _, option := range options {
+		if r.MaxOptionLength > 0 && utf8.RuneCountInString(option) > r.MaxOptionLength {
+			return nil, Error{
+				cause: fmt.Errorf("option %q is longer than limit of %d", option, r.MaxOptionLength),
+				helpText: fmt.Sprintf(
+					"Whoops, options can't be longer than %d characters!", r.MaxOptionLength),
+			}
+		}
+		if err := r.checkChars("option", option); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.applyDuplicatePolicy(options)
+}
+
+func (r Rules) checkChars(kind, value string) error {
+	if value == "" {
+		return Error{
+			cause:    fmt.Errorf("empty %s", kind),
+			helpText: fmt.Sprintf("Whoops, I can't save an empty %s!", kind),
+		}
+	}
+
+	if r.AllowedChars == nil {
+		return nil
+	}
+
+	for _, c := range value {
+		if !r.AllowedChars.MatchString(string(c)) {
+			return Error{
+				cause: fmt.Errorf("%s %q contains disallowed character %q", kind, value, c),
+				helpText: fmt.Sprintf(
+					"Whoops, the %s %q contains %q, which isn't allowed here!", kind, value, string(c)),
+			}
+		}
+	}
+	return nil
+}
+
+func (r Rules) applyDuplicatePolicy(options []string) ([]string, error) {
+	if r.Duplicates == AllowDuplicates {
+		return options, nil
+	}
+
+	var (
+		seen   = make(map[string]bool, len(options))
+		unique = make([]string, 0, len(options))
+	)
+	for _, option := range options {
+		if !seen[option] {
+			seen[option] = true
+			unique = append(unique, option)
+			continue
+		}
+		if r.Duplicates == RejectDuplicates {
+			return nil, Error{
+				cause: fmt.Errorf("duplicate option %q", option),
+				helpText: fmt.Sprintf(
+					"Whoops, %q appears more than once in that group! Please list each option only once.", option),
+			}
+		}
+	}
+	return slices.Clip(unique), nil
+}
+
+// checkGroupCount ensures that saving the named group will not exceed the
+// maximum number of groups in the partition, given the names of the groups
+// already saved there.
+func (r Rules) checkGroupCount(name string, existing []string) error {
+	if r.MaxGroups <= 0 || slices.Contains(existing, name) || len(existing) < r.MaxGroups {
+		return nil
+	}
+	return Error{
+		cause: errors.New("too many groups in partition"),
+		helpText: fmt.Sprintf(
+			"Whoops, this channel already has the maximum of %d groups! Please delete one before saving another.",
+			r.MaxGroups,
+		),
+	}
+}
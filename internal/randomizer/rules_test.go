@@ -0,0 +1,43 @@
+package randomizer
+
+import "testing"
+
+func TestRulesFromEnv(t *testing.T) {
+	t.Setenv("RANDOMIZER_MAX_OPTIONS", "10")
+	t.Setenv("RANDOMIZER_MAX_OPTION_LENGTH", "20")
+	t.Setenv("RANDOMIZER_MAX_GROUPS", "30")
+	t.Setenv("RANDOMIZER_DUPLICATE_OPTIONS", "remove")
+	t.Setenv("RANDOMIZER_ALLOWED_CHARS", `\p{L}_`)
+
+	rules, err := RulesFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rules.MaxOptions != 10 || rules.MaxOptionLength != 20 || rules.MaxGroups != 30 {
+		t.Errorf("unexpected limits in %+v", rules)
+	}
+	if rules.Duplicates != RemoveDuplicates {
+		t.Errorf("got duplicate policy %v, want %v", rules.Duplicates, RemoveDuplicates)
+	}
+	if !rules.AllowedChars.MatchString("é") || rules.AllowedChars.MatchString("-") {
+		t.Errorf("unexpected allowed character pattern %v", rules.AllowedChars)
+	}
+}
+
+func TestRulesFromEnvInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"RANDOMIZER_MAX_OPTIONS":       "-1",
+		"RANDOMIZER_MAX_GROUPS":        "lots",
+		"RANDOMIZER_DUPLICATE_OPTIONS": "ignore",
+		"RANDOMIZER_ALLOWED_CHARS":     `\p{Nope}`,
+	}
+	for key, value := range invalid {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := RulesFromEnv(); err == nil {
+				t.Errorf("RulesFromEnv succeeded with %s=%q", key, value)
+			}
+		})
+	}
+}
@@ -3,35 +3,50 @@ package randomizer
 import (
 	"context"
 	"fmt"
+	"slices"
 )
 
 func (a App) makeSelection(request request) (Result, error) {
-	options, err := a.expandArgs(request.Context, request.Args)
+	options, away, err := a.expandArgs(request.Context, request.Args)
 	if err != nil {
 		return Result{}, err
 	}
 
 	a.shuffle(options)
 
+	message := fmt.Sprintf("I randomized and got: %s.", inlinelist(options))
+	if len(away) > 0 {
+		message += fmt.Sprintf(" (I skipped %s, since they're away.)", conjlist("and", away))
+	}
+
 	return Result{
 		resultType: Selection,
-		message:    fmt.Sprintf("I randomized and got: %s.", inlinelist(options)),
+		message:    message,
+		options:    options,
 	}, nil
 }
 
-func (a App) expandArgs(ctx context.Context, args []string) ([]string, error) {
+// expandArgs returns the options to randomize given the user's arguments,
+// along with any options of a group that were skipped as away.
+func (a App) expandArgs(ctx context.Context, args []string) (options, away []string, err error) {
 	if len(args) == 1 {
+		if options, ok, err := a.expandReference(ctx, args[0]); ok || err != nil {
+			return options, nil, err
+		}
 		return a.expandGroup(ctx, args[0])
 	}
 
-	return args, nil
+	// Shuffling the options mustn't change the caller's arguments.
+	options, err = a.expandReferences(ctx, slices.Clone(args))
+	return options, nil, err
 }
 
-func (a App) expandGroup(ctx context.Context, group string) ([]string, error) {
-	expansion, err := a.store.Get(ctx, group)
+func (a App) expandGroup(ctx context.Context, group string) (options, away []string, err error) {
+	group = NormalizeGroupName(group)
+	_, expansion, err := a.lookupGroup(ctx, group)
 	if err != nil {
-		return nil, Error{
-			cause: err,
+		return nil, nil, Error{
+			cause: unavailable(err),
 			helpText: fmt.Sprintf(
 				"Whoops, I had trouble getting the %q group. Please try again later!",
 				group,
@@ -39,15 +54,36 @@ func (a App) expandGroup(ctx context.Context, group string) ([]string, error) {
 		}
 	}
 
-	if len(expansion) == 0 {
-		return nil, Error{
-			cause: fmt.Errorf("group %q not found", group),
+	if len(expansion.Options) == 0 {
+		return nil, nil, Error{
+			cause: fmt.Errorf("%w: %q", ErrGroupNotFound, group),
+			helpText: fmt.Sprintf(
+				`Whoops, I couldn't find the %q group in this channel.%s (Type "%s help" to learn more about groups!)`,
+				group, a.suggestGroups(ctx, group), a.name,
+			),
+		}
+	}
+
+	// Shuffling the options mustn't change the store's copy of the group, which
+	// in-memory stores may share with us.
+	options, err = a.expandReferences(ctx, slices.Clone(expansion.Options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options, away, err = a.removeAway(ctx, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(options) == 0 {
+		return nil, nil, Error{
+			cause: fmt.Errorf("all options in group %q are away", group),
 			helpText: fmt.Sprintf(
-				`Whoops, I couldn't find the %q group in this channel. (Type "%s help" to learn more about groups!)`,
+				`Whoops, everything in the %q group is away right now! (Type "%s /away" to see who's away.)`,
 				group, a.name,
 			),
 		}
 	}
 
-	return expansion, nil
+	return options, away, nil
 }
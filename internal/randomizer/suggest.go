@@ -0,0 +1,91 @@
+package randomizer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// maxSuggestions is the most group names that a single "did you mean"
+// suggestion will list.
+const maxSuggestions = 3
+
+// suggestGroups returns a sentence suggesting up to [maxSuggestions] saved
+// groups with names similar to the provided name, with a leading space to
+// simplify concatenation into help text. If no groups are similar enough, or
+// the groups can't be listed, it returns an empty string.
+//
+// suggestGroups should only be called after a lookup for name fails, so that
+// successful lookups don't incur the cost of listing groups.
+func (a App) suggestGroups(ctx context.Context, name string) string {
+	groups, err := a.store.List(ctx)
+	if err != nil {
+		return ""
+	}
+	if aliases, ok := a.store.(AliasStore); ok {
+		if all, err := aliases.ListAliases(ctx); err == nil {
+			groups = slices.AppendSeq(groups, maps.Keys(all))
+		}
+	}
+
+	type candidate struct {
+		group    string
+		distance int
+	}
+	var (
+		target     = []rune(name)
+		candidates []candidate
+	)
+	for _, group := range groups {
+		distance := editDistance(target, []rune(group))
+		if distance <= maxSuggestionDistance(len(target)) {
+			candidates = append(candidates, candidate{group, distance})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	slices.SortFunc(candidates, func(x, y candidate) int {
+		return cmp.Or(cmp.Compare(x.distance, y.distance), cmp.Compare(x.group, y.group))
+	})
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.group
+	}
+	return fmt.Sprintf(" Did you mean %s?", conjlist("or", quoteall(names)))
+}
+
+// maxSuggestionDistance returns the largest edit distance at which a group is
+// considered similar to a name of the provided length, so that short names
+// don't match every other short name.
+func maxSuggestionDistance(length int) int {
+	return max(1, length/3)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		curr[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,24 @@
+package randomizer
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"lunch", "lunch", 0},
+		{"lunch", "lnch", 1},
+		{"lunch", "launch", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tc := range testCases {
+		if got := editDistance([]rune(tc.a), []rune(tc.b)); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
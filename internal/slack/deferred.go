@@ -0,0 +1,112 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+const (
+	// DefaultDeferAfter is the default time that App waits for the randomizer
+	// to finish before acknowledging a slash command and deferring its result,
+	// leaving a comfortable margin within the 3-second response time limit that
+	// Slack imposes on slash commands.
+	DefaultDeferAfter = 2 * time.Second
+
+	// MaxDeferredDuration limits the time that App spends on a deferred slash
+	// command. Slack accepts posts to a response URL for up to 30 minutes, but
+	// a result that takes this long would be of little use.
+	MaxDeferredDuration = 30 * time.Second
+)
+
+// DeferAfterFromEnv returns the value of SLACK_DEFER_AFTER as a Go duration, or
+// DefaultDeferAfter if it is unset. A value of 0 disables deferred responses.
+func DeferAfterFromEnv() (time.Duration, error) {
+	env, ok := os.LookupEnv("SLACK_DEFER_AFTER")
+	if !ok {
+		return DefaultDeferAfter, nil
+	}
+
+	deferAfter, err := time.ParseDuration(env)
+	if err != nil {
+		return 0, fmt.Errorf("SLACK_DEFER_AFTER is not a valid Go duration: %w", err)
+	}
+	return deferAfter, nil
+}
+
+// outcome holds the return values of a randomizer run.
+type outcome struct {
+	result randomizer.Result
+	err    error
+}
+
+// runDeferrable runs the randomizer for an invocation in the background, and
+// returns its outcome if it finishes within the DeferAfter budget.
+//
+// If the budget runs out first, runDeferrable returns false, and the background
+// work posts the outcome to responseURL once it's ready. The caller must then
+// acknowledge the request without a result.
+func (a App) runDeferrable(ctx context.Context, inv invocation, responseURL string) (outcome, bool) {
+	var (
+		outcomes = make(chan outcome) // unbuffered, so an outcome is never lost
+		deferred = make(chan struct{})
+	)
+
+	// The background work must outlive the request, but keeps the values of its
+	// context for logging and tracing.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), MaxDeferredDuration)
+	a.runInBackground(func() {
+		defer cancel()
+
+		var out outcome
+		out.result, out.err = a.runRandomizer(ctx, inv)
+		select {
+		case outcomes <- out:
+			return
+		case <-deferred:
+		}
+
+		if out.err != nil {
+			a.logErr(out.err, "Failed to run randomizer")
+		}
+		if err := a.respond(ctx, responseURL, a.outcomeResponse(inv, out)); err != nil {
+			a.logErr(err, "Failed to post deferred response")
+		}
+	})
+
+	timer := time.NewTimer(a.DeferAfter)
+	defer timer.Stop()
+
+	select {
+	case out := <-outcomes:
+		return out, true
+	case <-timer.C:
+		close(deferred)
+		return outcome{}, false
+	}
+}
+
+func (a App) runInBackground(task func()) {
+	if a.RunInBackground != nil {
+		a.RunInBackground(task)
+	} else {
+		go task()
+	}
+}
+
+func (a App) outcomeResponse(inv invocation, out outcome) response {
+	if out.err != nil {
+		return errorResponse(out.err)
+	}
+	return a.resultResponse(inv, out.result)
+}
+
+// acknowledge tells Slack that App received a slash command, without showing
+// anything to the user right away.
+func acknowledge(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+}
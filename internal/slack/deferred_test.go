@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+// slowStore is a randomizer.Store whose reads block until its release channel
+// is closed.
+type slowStore struct {
+	rndtest.Store
+	release chan struct{}
+}
+
+func (s slowStore) Get(ctx context.Context, name string) ([]string, error) {
+	<-s.release
+	return s.Store.Get(ctx, name)
+}
+
+func TestDeferredResponses(t *testing.T) {
+	testCases := []struct {
+		description string
+		slow        bool
+		text        string
+		wantBody    []string
+		wantPosted  []string
+	}{
+		{
+			description: "fast store",
+			text:        "test",
+			wantBody:    []string{"I randomized and got"},
+		},
+		{
+			description: "slow store",
+			slow:        true,
+			text:        "test",
+			wantPosted:  []string{`"response_type":"in_channel"`, "I randomized and got"},
+		},
+		{
+			description: "slow store with error",
+			slow:        true,
+			text:        "/show missing",
+			wantPosted:  []string{`"response_type":"ephemeral"`, "Whoops"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			posted := make(chan string, 1)
+			responseSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				posted <- string(body)
+			}))
+			defer responseSrv.Close()
+
+			store := slowStore{
+				Store:   rndtest.Store{"test": {"one", "two"}},
+				release: make(chan struct{}),
+			}
+			if !tc.slow {
+				close(store.release)
+			}
+
+			app := App{
+				TokenProvider: StaticToken("right"),
+				StoreFactory:  func(_ string) randomizer.Store { return store },
+				HTTPClient:    responseSrv.Client(),
+				DeferAfter:    10 * time.Millisecond,
+			}
+
+			params := makeTestParams(tc.text)
+			params.Set("response_url", responseSrv.URL)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			app.ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != http.StatusOK {
+				t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+			}
+			body := resp.Body.String()
+			for _, want := range tc.wantBody {
+				if !strings.Contains(body, want) {
+					t.Errorf("response missing %q\n%s", want, body)
+				}
+			}
+			if tc.wantBody == nil && body != "" {
+				t.Errorf("got response body before store finished\n%s", body)
+			}
+
+			if tc.slow {
+				close(store.release)
+			}
+
+			if tc.wantPosted == nil {
+				select {
+				case body := <-posted:
+					t.Errorf("unexpected post to response URL\n%s", body)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case body := <-posted:
+				for _, want := range tc.wantPosted {
+					if !strings.Contains(body, want) {
+						t.Errorf("deferred response missing %q\n%s", want, body)
+					}
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no deferred response posted to response URL")
+			}
+		})
+	}
+}
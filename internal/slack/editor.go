@@ -0,0 +1,162 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+const (
+	editorCallbackID = "edit_group"
+	optionsBlockID   = "options"
+	optionsActionID  = "options"
+)
+
+// Slack limits the length of the initial value of a text input, and of the
+// private metadata that a view carries. Groups that would exceed these limits
+// are shown as text instead of in the editor.
+const (
+	maxInputLength           = 3000
+	maxPrivateMetadataLength = 3000
+)
+
+// modalView represents a Block Kit modal view. It includes only the fields
+// that the randomizer uses.
+type modalView struct {
+	Type            string  `json:"type"`
+	CallbackID      string  `json:"callback_id"`
+	Title           text    `json:"title"`
+	Submit          text    `json:"submit"`
+	Close           text    `json:"close"`
+	PrivateMetadata string  `json:"private_metadata"`
+	Blocks          []block `json:"blocks"`
+}
+
+// plainTextInput represents a Block Kit plain-text input element.
+type plainTextInput struct {
+	Type         string `json:"type"`
+	ActionID     string `json:"action_id"`
+	Multiline    bool   `json:"multiline"`
+	InitialValue string `json:"initial_value,omitempty"`
+}
+
+// editorMetadata is the private metadata of the group editor, which holds the
+// details of the original invocation that the submission needs.
+type editorMetadata struct {
+	Command     string `json:"command"`
+	ChannelID   string `json:"channel_id"`
+	ResponseURL string `json:"response_url"`
+	Group       string `json:"group"`
+}
+
+// viewErrors is a response to a view submission that shows errors on the
+// blocks of the view, keeping it open.
+type viewErrors struct {
+	ResponseAction string            `json:"response_action"`
+	Errors         map[string]string `json:"errors"`
+}
+
+// openEditor opens a modal that lists the options of the group in an
+// EditingGroup result one per line, for the user to change and submit. It
+// reports whether it opened the editor; if not, the caller should show the
+// result's message instead.
+func (a App) openEditor(ctx context.Context, inv invocation, triggerID, responseURL string, result randomizer.Result) bool {
+	if a.Client == nil || triggerID == "" {
+		return false
+	}
+
+	initial := strings.Join(result.Options(), "\n")
+	metadata, err := json.Marshal(editorMetadata{
+		Command:     inv.Command,
+		ChannelID:   inv.ChannelID,
+		ResponseURL: responseURL,
+		Group:       result.GroupName(),
+	})
+	if err != nil || len(initial) > maxInputLength || len(metadata) > maxPrivateMetadataLength {
+		return false
+	}
+
+	view := modalView{
+		Type:            "modal",
+		CallbackID:      editorCallbackID,
+		Title:           text{Type: "plain_text", Text: "Edit Group"},
+		Submit:          text{Type: "plain_text", Text: "Save"},
+		Close:           text{Type: "plain_text", Text: "Cancel"},
+		PrivateMetadata: string(metadata),
+		Blocks: []block{{
+			Type:    "input",
+			BlockID: optionsBlockID,
+			Label:   &text{Type: "plain_text", Text: fmt.Sprintf("Options in the %q group", result.GroupName())},
+			Element: plainTextInput{
+				Type:         "plain_text_input",
+				ActionID:     optionsActionID,
+				Multiline:    true,
+				InitialValue: initial,
+			},
+			Hint: &text{Type: "plain_text", Text: "Put each option on its own line."},
+		}},
+	}
+
+	err = a.Client.OpenView(withInstallation(ctx, inv.Workspace), triggerID, view)
+	if err != nil {
+		a.logErr(err, "Failed to open group editor")
+		return false
+	}
+	return true
+}
+
+// submitEditor saves the options submitted through the group editor, and
+// returns the response to the submission. If the randomizer rejects the
+// options, the response shows the problem on the options field and keeps the
+// editor open. Otherwise, the editor closes, and the result is posted to the
+// response URL of the original command, or shown in the Home tab.
+func (a App) submitEditor(ctx context.Context, payload interactionPayload) (any, error) {
+	var metadata editorMetadata
+	if err := json.Unmarshal([]byte(payload.View.PrivateMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("decoding editor metadata: %w", err)
+	}
+
+	args := []string{"/save", metadata.Group}
+	for line := range strings.Lines(payload.View.State.Values[optionsBlockID][optionsActionID].Value) {
+		if option := strings.TrimSpace(line); option != "" {
+			args = append(args, option)
+		}
+	}
+
+	inv := invocation{
+		Command:   metadata.Command,
+		Workspace: payload.workspace(),
+		ChannelID: metadata.ChannelID,
+		UserID:    payload.User.ID,
+		Args:      args,
+	}
+	result, err := a.runRandomizer(ctx, inv)
+	if err != nil {
+		a.logErr(err, "Failed to run randomizer")
+		return viewErrors{
+			ResponseAction: "errors",
+			Errors:         map[string]string{optionsBlockID: webhook.HelpText(err)},
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), MaxDeferredDuration)
+	a.runInBackground(func() {
+		defer cancel()
+		// Editors opened from the Home tab have no response URL, so we show the
+		// changes by refreshing the Home tab instead.
+		if metadata.ResponseURL == "" {
+			if a.Client != nil {
+				a.publishHome(ctx, inv.Workspace, inv.UserID)
+			}
+			return
+		}
+		if err := a.respond(ctx, metadata.ResponseURL, a.resultResponse(inv, result)); err != nil {
+			a.logErr(err, "Failed to post edited group")
+		}
+	})
+	return nil, nil
+}
@@ -0,0 +1,208 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestEditCommand(t *testing.T) {
+	testCases := []struct {
+		description string
+		triggerID   string
+		client      bool
+		wantView    bool
+		wantBody    string
+	}{
+		{
+			description: "opening the editor",
+			triggerID:   "trigger",
+			client:      true,
+			wantView:    true,
+		},
+		{
+			description: "without a Web API client",
+			triggerID:   "trigger",
+			wantBody:    "To change them, use the /save flag",
+		},
+		{
+			description: "when the editor fails to open",
+			client:      true,
+			wantBody:    "To change them, use the /save flag",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			fake := &fakeSlack{Token: "xoxb-test"}
+			app := App{
+				TokenProvider: StaticToken("right"),
+				StoreFactory: func(_ string) randomizer.Store {
+					return rndtest.Store{"test": {"two", "one"}}
+				},
+			}
+			if tc.client {
+				app.Client = fake.start(t)
+			}
+
+			params := makeTestParams("/edit test")
+			params.Set("trigger_id", tc.triggerID)
+			params.Set("response_url", "https://hooks.slack.com/commands/1")
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			app.ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != http.StatusOK {
+				t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+			}
+			if body := resp.Body.String(); tc.wantBody == "" && body != "" || !strings.Contains(body, tc.wantBody) {
+				t.Errorf("got body %q, want one containing %q", body, tc.wantBody)
+			}
+
+			views := fake.Views()
+			if !tc.wantView {
+				if len(views) > 0 {
+					t.Errorf("unexpected views opened: %v", views)
+				}
+				return
+			}
+			if len(views) != 1 {
+				t.Fatalf("got %d views opened, want 1", len(views))
+			}
+
+			var view struct {
+				modalView
+				Blocks []struct {
+					Element plainTextInput `json:"element"`
+				} `json:"blocks"`
+			}
+			if err := json.Unmarshal([]byte(views[0]), &view); err != nil {
+				t.Fatalf("failed to decode view: %v", err)
+			}
+			if len(view.Blocks) != 1 || view.Blocks[0].Element.InitialValue != "one\ntwo" {
+				t.Errorf("view not prefilled with options: %s", views[0])
+			}
+
+			var metadata editorMetadata
+			json.Unmarshal([]byte(view.PrivateMetadata), &metadata)
+			want := editorMetadata{
+				Command:     "/randomize",
+				ChannelID:   "C12345678",
+				ResponseURL: "https://hooks.slack.com/commands/1",
+				Group:       "test",
+			}
+			if metadata != want {
+				t.Errorf("got metadata %+v, want %+v", metadata, want)
+			}
+		})
+	}
+}
+
+func TestEditorSubmission(t *testing.T) {
+	testCases := []struct {
+		description string
+		value       string
+		wantBody    string
+		wantPosted  string
+		wantStore   rndtest.Store
+	}{
+		{
+			description: "saving new options",
+			value:       "three\n  four \n\nfive six\n",
+			wantPosted:  `The \"test\" group was saved`,
+			wantStore:   rndtest.Store{"test": {"five six", "four", "three"}},
+		},
+		{
+			description: "saving too few options",
+			value:       "three\n",
+			wantBody:    `{"response_action":"errors","errors":{"options":"Whoops, I need at least two options to save a group!"}}`,
+			wantStore:   rndtest.Store{"test": {"one", "two"}},
+		},
+		{
+			description: "saving duplicate options",
+			value:       "three\nthree",
+			wantBody:    `"response_action":"errors"`,
+			wantStore:   rndtest.Store{"test": {"one", "two"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			responses := make(chan string, 1)
+			responseSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				responses <- string(body)
+			}))
+			defer responseSrv.Close()
+
+			store := rndtest.Store{"test": {"one", "two"}}
+			app := App{
+				TokenProvider:   StaticToken("right"),
+				StoreFactory:    func(_ string) randomizer.Store { return store },
+				HTTPClient:      responseSrv.Client(),
+				RunInBackground: func(task func()) { task() },
+			}
+
+			metadata, _ := json.Marshal(editorMetadata{
+				Command:     "/randomize",
+				ChannelID:   "C12345678",
+				ResponseURL: responseSrv.URL,
+				Group:       "test",
+			})
+			payload := map[string]any{
+				"type":  "view_submission",
+				"token": "right",
+				"user":  map[string]string{"id": "U2"},
+				"view": map[string]any{
+					"callback_id":      editorCallbackID,
+					"private_metadata": string(metadata),
+					"state": map[string]any{
+						"values": map[string]any{
+							optionsBlockID: map[string]any{
+								optionsActionID: map[string]string{"type": "plain_text_input", "value": tc.value},
+							},
+						},
+					},
+				},
+			}
+			payloadJSON, _ := json.Marshal(payload)
+			form := url.Values{"payload": {string(payloadJSON)}}
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			app.InteractionHandler().ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != http.StatusOK {
+				t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+			}
+			if body := strings.TrimSpace(resp.Body.String()); tc.wantBody == "" && body != "" || !strings.Contains(body, tc.wantBody) {
+				t.Errorf("got body %q, want one containing %q", body, tc.wantBody)
+			}
+
+			select {
+			case posted := <-responses:
+				if tc.wantPosted == "" || !strings.Contains(posted, tc.wantPosted) {
+					t.Errorf("unexpected response posted: %s", posted)
+				}
+			default:
+				if tc.wantPosted != "" {
+					t.Error("no response posted to response URL")
+				}
+			}
+
+			if !reflect.DeepEqual(store, tc.wantStore) {
+				t.Errorf("unexpected store state\ngot:  %v\nwant: %v", store, tc.wantStore)
+			}
+		})
+	}
+}
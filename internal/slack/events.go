@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+// eventPayload represents the body of a request from the Slack Events API. It
+// includes only the fields that the randomizer uses.
+type eventPayload struct {
+	Type           string `json:"type"`
+	Token          string `json:"token"`
+	Challenge      string `json:"challenge"`
+	EventID        string `json:"event_id"`
+	TeamID         string `json:"team_id"`
+	EnterpriseID   string `json:"enterprise_id"`
+	Authorizations []struct {
+		IsEnterpriseInstall bool `json:"is_enterprise_install"`
+	} `json:"authorizations"`
+	Event struct {
+		Type     string `json:"type"`
+		Tab      string `json:"tab"`
+		User     string `json:"user"`
+		BotID    string `json:"bot_id"`
+		Channel  string `json:"channel"`
+		Text     string `json:"text"`
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts"`
+		Tokens   struct {
+			Bot []string `json:"bot"`
+		} `json:"tokens"`
+	} `json:"event"`
+}
+
+func (p eventPayload) workspace() workspace {
+	return workspace{
+		EnterpriseID:      p.EnterpriseID,
+		TeamID:            p.TeamID,
+		EnterpriseInstall: len(p.Authorizations) > 0 && p.Authorizations[0].IsEnterpriseInstall,
+	}
+}
+
+// EventHandler returns a handler for requests from the Slack Events API, which
+// lets users invoke the randomizer by mentioning its bot user, as in
+// "@randomizer one two three". Configure its URL as the "Request URL" under
+// "Event Subscriptions" in the Slack app configuration, and subscribe to the
+// app_mention bot event.
+//
+// The handler verifies requests in the same way as App.ServeHTTP, and replies
+// to each mention in a thread through the Web API, so App.Client must be
+// non-nil. As Slack expects a quick response to every event, the handler
+// acknowledges each event before running the randomizer in the background.
+//
+// If the app_home_opened bot event is also subscribed and the "Home Tab" is
+// enabled under "App Home," the handler publishes a Home tab that lists the
+// groups in each of the user's channels. This requires a store that
+// implements PartitionLister.
+//
+// With OAuth, the handler also deletes the bot token of a workspace when it
+// receives the app_uninstalled or tokens_revoked event for that workspace.
+func (a App) EventHandler() http.Handler {
+	return http.HandlerFunc(a.serveEvent)
+}
+
+func (a App) serveEvent(w http.ResponseWriter, r *http.Request) {
+	_, body, ok := a.readRequest(w, r, func(_ url.Values, body []byte) string {
+		var payload eventPayload
+		json.Unmarshal(body, &payload)
+		return payload.Token
+	})
+	if !ok {
+		return
+	}
+
+	var payload eventPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		a.logErr(err, "Failed to decode event payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch payload.Type {
+	case "url_verification":
+		webhook.WriteJSON(w, a.Logger, map[string]string{"challenge": payload.Challenge})
+		return
+	case "event_callback":
+	default:
+		return
+	}
+
+	// Slack retries events that it couldn't deliver. Since we acknowledge
+	// events before handling them, a retry after a timeout most likely means
+	// that the original delivery made it through, and that handling the retry
+	// would reply to the same mention twice. The RetryCache recognizes other
+	// retries of events that this process handled.
+	if r.Header.Get("X-Slack-Retry-Reason") == "http_timeout" {
+		return
+	}
+
+	if err := a.handleEvent(r.Context(), payload); err != nil {
+		a.logErr(err, "Failed to handle event")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// handleEvent handles a verified event callback. It returns as soon as the
+// event is safe to acknowledge, and finishes any replies in the background.
+//
+// If the event is a redelivery of one that App already handled, handleEvent
+// does nothing, so that it doesn't reply to the same mention twice.
+func (a App) handleEvent(ctx context.Context, payload eventPayload) error {
+	_, retried, err := a.RetryCache.do(ctx, retryKey("event", payload.EventID), func() (any, error) {
+		return nil, a.runEvent(ctx, payload)
+	})
+	if retried && err == nil {
+		a.logRetry(payload.EventID)
+	}
+	return err
+}
+
+func (a App) runEvent(ctx context.Context, payload eventPayload) error {
+	event := payload.Event
+	if event.Type == "app_uninstalled" || event.Type == "tokens_revoked" && len(event.Tokens.Bot) > 0 {
+		if a.OAuth == nil {
+			return nil
+		}
+		if err := a.uninstall(ctx, payload.workspace()); err != nil {
+			return fmt.Errorf("deleting bot token: %w", err)
+		}
+		return nil
+	}
+
+	if event.Type == "app_home_opened" && event.Tab == "home" {
+		if a.Client == nil {
+			return errors.New("no Web API client configured to publish Home tab")
+		}
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), MaxDeferredDuration)
+		a.runInBackground(func() {
+			defer cancel()
+			a.publishHome(ctx, payload.workspace(), event.User)
+		})
+		return nil
+	}
+
+	if event.Type != "app_mention" || event.BotID != "" {
+		return nil
+	}
+	if a.Client == nil {
+		return errors.New("no Web API client configured to reply to mention")
+	}
+
+	threadTS := event.ThreadTS
+	if threadTS == "" {
+		threadTS = event.TS
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), MaxDeferredDuration)
+	a.runInBackground(func() {
+		defer cancel()
+		a.replyToMention(ctx, payload.workspace(), event.Channel, event.User, threadTS, event.Text)
+	})
+	return nil
+}
+
+// leadingMentionPattern matches the mention of the randomizer's bot user at
+// the start of the text of an app_mention event.
+var leadingMentionPattern = regexp.MustCompile(`^\s*(<@[A-Z0-9]+(?:\|[^>]*)?>)`)
+
+// replyToMention runs the randomizer with the text that follows the mention of
+// its bot user in a message, and replies to the message in a thread.
+func (a App) replyToMention(ctx context.Context, ws workspace, channelID, userID, threadTS, text string) {
+	inv := invocation{Workspace: ws, ChannelID: channelID, UserID: userID}
+	if match := leadingMentionPattern.FindStringSubmatchIndex(text); match != nil {
+		// Help text that refers to the bot user by its mention reads naturally
+		// in Slack.
+		inv.Command = text[match[2]:match[3]]
+		text = text[match[1]:]
+	}
+	inv.Args = strings.Fields(text)
+
+	var out outcome
+	out.result, out.err = a.runRandomizer(ctx, inv)
+	if out.err != nil {
+		a.logErr(out.err, "Failed to run randomizer")
+	}
+
+	resp := a.outcomeResponse(inv, out)
+	err := a.Client.PostMessage(withInstallation(ctx, ws), Message{Channel: channelID, ThreadTS: threadTS, Text: resp.Text})
+	if err != nil {
+		a.logErr(err, "Failed to reply to mention")
+	}
+}
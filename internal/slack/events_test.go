@@ -0,0 +1,165 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestEvents(t *testing.T) {
+	testCases := []struct {
+		description string
+		body        string
+		retryReason string
+		wantBody    string
+		wantMessage *Message
+	}{
+		{
+			description: "url verification",
+			body:        `{"type":"url_verification","token":"right","challenge":"abc123"}`,
+			wantBody:    `{"challenge":"abc123"}`,
+		},
+		{
+			description: "mention",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> test","ts":"1.1"}}`,
+			wantMessage: &Message{Channel: "C12345678", ThreadTS: "1.1", Text: "I randomized and got"},
+		},
+		{
+			description: "mention in thread",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> /show test","ts":"1.2","thread_ts":"1.1"}}`,
+			wantMessage: &Message{Channel: "C12345678", ThreadTS: "1.1", Text: "The \"test\" group has"},
+		},
+		{
+			description: "mention with error",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> /show missing","ts":"1.1"}}`,
+			wantMessage: &Message{Channel: "C12345678", ThreadTS: "1.1", Text: "Whoops"},
+		},
+		{
+			description: "mention without arguments",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT>","ts":"1.1"}}`,
+			wantMessage: &Message{Channel: "C12345678", ThreadTS: "1.1", Text: "<@U0BOT> /save snacks"},
+		},
+		{
+			description: "mention from bot",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","bot_id":"B1","channel":"C12345678","text":"<@U0BOT> test","ts":"1.1"}}`,
+		},
+		{
+			description: "retry after timeout",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> test","ts":"1.1"}}`,
+			retryReason: "http_timeout",
+		},
+		{
+			description: "retry after error",
+			body:        `{"type":"event_callback","token":"right","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> test","ts":"1.1"}}`,
+			retryReason: "http_error",
+			wantMessage: &Message{Channel: "C12345678", ThreadTS: "1.1", Text: "I randomized and got"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			fake := &fakeSlack{Token: "xoxb-test"}
+			app := App{
+				TokenProvider: StaticToken("right"),
+				StoreFactory: func(_ string) randomizer.Store {
+					return rndtest.Store{"test": {"one", "two"}}
+				},
+				Client:          fake.start(t),
+				RunInBackground: func(task func()) { task() },
+			}
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.retryReason != "" {
+				req.Header.Set("X-Slack-Retry-Num", "1")
+				req.Header.Set("X-Slack-Retry-Reason", tc.retryReason)
+			}
+			app.EventHandler().ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != http.StatusOK {
+				t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+			}
+			if got := strings.TrimSpace(resp.Body.String()); got != tc.wantBody {
+				t.Errorf("got body %q, want %q", got, tc.wantBody)
+			}
+
+			messages := fake.Messages()
+			if tc.wantMessage == nil {
+				if len(messages) > 0 {
+					t.Errorf("unexpected messages posted: %v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 {
+				t.Fatalf("got %d messages posted, want 1: %v", len(messages), messages)
+			}
+			got, want := messages[0], *tc.wantMessage
+			if got.Channel != want.Channel || got.ThreadTS != want.ThreadTS || !strings.Contains(got.Text, want.Text) {
+				t.Errorf("got message %+v, want one like %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestEventRetryCache(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-test"}
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory: func(_ string) randomizer.Store {
+			return rndtest.Store{"test": {"one", "two"}}
+		},
+		Client:          fake.start(t),
+		RetryCache:      &RetryCache{},
+		RunInBackground: func(task func()) { task() },
+	}
+
+	deliveries := []struct {
+		eventID     string
+		retryReason string
+		wantPosted  int
+	}{
+		{eventID: "Ev1", wantPosted: 1},
+		{eventID: "Ev1", retryReason: "http_error", wantPosted: 1},
+		{eventID: "Ev2", wantPosted: 2},
+	}
+	for _, d := range deliveries {
+		body := `{"type":"event_callback","token":"right","event_id":"` + d.eventID + `","event":{"type":"app_mention","user":"U2","channel":"C12345678","text":"<@U0BOT> test","ts":"1.1"}}`
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if d.retryReason != "" {
+			req.Header.Set("X-Slack-Retry-Num", "1")
+			req.Header.Set("X-Slack-Retry-Reason", d.retryReason)
+		}
+		app.EventHandler().ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+		}
+		if got := len(fake.Messages()); got != d.wantPosted {
+			t.Errorf("after delivery of %s (retry reason %q): got %d messages posted, want %d", d.eventID, d.retryReason, got, d.wantPosted)
+		}
+	}
+}
+
+func TestEventInvalidToken(t *testing.T) {
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return rndtest.Store(nil) },
+	}
+
+	body := `{"type":"url_verification","token":"wrong","challenge":"abc123"}`
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	app.EventHandler().ServeHTTP(resp, req)
+
+	if resp.Result().StatusCode != http.StatusForbidden {
+		t.Errorf("wrong status for invalid token: got %v, want %v", resp.Result().StatusCode, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,375 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// PartitionLister is implemented by stores that can list every partition in
+// their database, rather than only their own. The App Home tab uses it to find
+// the channels with saved groups.
+type PartitionLister interface {
+	// ListPartitions returns the names of all partitions with saved data. If
+	// there are none, it returns an empty list with a nil error.
+	ListPartitions(ctx context.Context) (partitions []string, err error)
+}
+
+const (
+	homeRandomizeActionID = "home_randomize"
+	homeEditActionID      = "home_edit"
+)
+
+// Slack limits the number of blocks in a Home tab. Groups that would exceed
+// this limit are left out.
+const maxHomeBlocks = 100
+
+// maxHomeChannels is the most channels that fit in a Home tab, with a heading
+// and at least one group (of two blocks) each. The Home tab stops looking for
+// the user's channels once it finds more than this.
+const maxHomeChannels = (maxHomeBlocks - 1) / 3
+
+// DefaultHomeCacheTTL is the default time that a HomeCache remembers the
+// partitions of an installation.
+const DefaultHomeCacheTTL = 5 * time.Minute
+
+// HomeCache remembers the partitions of each installation for the App Home tab,
+// so that opening the tab doesn't list every partition in the store each time.
+// Listing partitions can be expensive, as with DynamoDB, which has to scan the
+// whole table. Channels that get their first groups while the cache remembers
+// an installation's partitions won't appear in the Home tab until it forgets
+// them.
+type HomeCache struct {
+	// TTL sets how long the cache remembers each installation's partitions. If
+	// zero, the cache uses DefaultHomeCacheTTL.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]homeCacheEntry
+	now     func() time.Time // Overridden in tests for predictable behavior
+}
+
+type homeCacheEntry struct {
+	partitions []string
+	expires    time.Time
+}
+
+// partitions returns the partitions of an installation, calling load if the
+// cache doesn't remember them. A nil HomeCache calls load every time.
+func (c *HomeCache) partitions(installation string, load func() ([]string, error)) ([]string, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[installation]
+	c.mu.Unlock()
+	if ok && c.timeNow().Before(entry.expires) {
+		return entry.partitions, nil
+	}
+
+	// As with the members cache, concurrent loads for the same installation are
+	// harmless, as the last one to finish simply overwrites the others.
+	partitions, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.timeNow()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]homeCacheEntry)
+	}
+	c.entries[installation] = homeCacheEntry{partitions: partitions, expires: now.Add(c.ttl())}
+	return partitions, nil
+}
+
+func (c *HomeCache) ttl() time.Duration {
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return DefaultHomeCacheTTL
+}
+
+func (c *HomeCache) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// homeView represents a Block Kit Home tab view.
+type homeView struct {
+	Type   string  `json:"type"`
+	Blocks []block `json:"blocks"`
+}
+
+// homeActionValue is the value of a button in the Home tab, which identifies
+// the group that the button acts on.
+type homeActionValue struct {
+	ChannelID string `json:"channel_id"`
+	Group     string `json:"group"`
+}
+
+// homeChannel holds the groups of a channel shown in the Home tab.
+type homeChannel struct {
+	ID     string
+	Groups map[string]randomizer.Group
+}
+
+// publishHome publishes the Home tab for a user, listing the groups of every
+// channel in the workspace that the user belongs to.
+func (a App) publishHome(ctx context.Context, ws workspace, userID string) {
+	ctx = withInstallation(ctx, ws)
+
+	var view homeView
+	channels, err := a.homeChannels(ctx, ws, userID)
+	if err != nil {
+		a.logErr(err, "Failed to find groups for Home tab")
+		view = homeView{Type: "home", Blocks: []block{
+			sectionBlock("Whoops, I had trouble finding the groups in your channels. Please try again later!"),
+		}}
+	} else {
+		view = buildHomeView(channels)
+	}
+
+	if err := a.Client.PublishView(ctx, userID, view); err != nil {
+		a.logErr(err, "Failed to publish Home tab")
+	}
+}
+
+// homeChannels returns the channels in the workspace that have saved groups
+// and that the user belongs to, ordered by ID.
+func (a App) homeChannels(ctx context.Context, ws workspace, userID string) ([]homeChannel, error) {
+	installation := ws.installation()
+	if installation == "" {
+		return nil, errors.New("no workspace for Home tab")
+	}
+	lister, ok := a.StoreFactory(installation).(PartitionLister)
+	if !ok {
+		return nil, errors.New("store can't list partitions")
+	}
+	partitions, err := a.HomeCache.partitions(installation, func() ([]string, error) {
+		partitions, err := lister.ListPartitions(ctx)
+		slices.Sort(partitions)
+		return partitions, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing partitions: %w", err)
+	}
+
+	var channels []homeChannel
+	for _, partition := range partitions {
+		// Finding one more channel than fits is enough for the Home tab to say
+		// that some groups are missing, without looking up the members of every
+		// remaining channel.
+		if len(channels) > maxHomeChannels {
+			break
+		}
+
+		channelID, ok := a.channelOfPartition(ws, partition)
+		if !ok {
+			continue
+		}
+
+		// The randomizer can't see the members of every channel, like private
+		// channels that it's been removed from, so it leaves those out.
+		members, err := a.Client.ChannelMembers(ctx, channelID)
+		if err != nil || !slices.Contains(members, userID) {
+			continue
+		}
+
+		groups, err := listGroups(ctx, a.StoreFactory(partition))
+		if err != nil {
+			return nil, fmt.Errorf("listing groups in %q: %w", partition, err)
+		}
+		if len(groups) > 0 {
+			channels = append(channels, homeChannel{ID: channelID, Groups: groups})
+		}
+	}
+	return channels, nil
+}
+
+// channelOfPartition returns the ID of the channel whose groups are kept in the
+// partition, if the partition belongs to a channel in the workspace.
+func (a App) channelOfPartition(ws workspace, partition string) (string, bool) {
+	if a.OAuth != nil {
+		prefix := joinPartition(ws.EnterpriseID, ws.TeamID) + ":"
+		var ok bool
+		if partition, ok = strings.CutPrefix(partition, prefix); !ok {
+			return "", false
+		}
+	}
+	return partition, partition != "" && !strings.Contains(partition, ":")
+}
+
+// listGroups returns all of the groups in a store, with their metadata if the
+// store supports it.
+func listGroups(ctx context.Context, store randomizer.Store) (map[string]randomizer.Group, error) {
+	if groups, ok := store.(randomizer.GroupStore); ok {
+		return groups.ListGroups(ctx)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]randomizer.Group, len(names))
+	for _, name := range names {
+		options, err := store.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		groups[name] = randomizer.Group{Options: options}
+	}
+	return groups, nil
+}
+
+// buildHomeView returns a Home tab that shows the groups of each channel, with
+// buttons to randomize or edit each group.
+func buildHomeView(channels []homeChannel) homeView {
+	blocks := []block{{Type: "header", Text: &text{Type: "plain_text", Text: "Groups in Your Channels"}}}
+	if len(channels) == 0 {
+		blocks = append(blocks, sectionBlock(
+			"Whoops, none of your channels have any groups. (Use the /save flag in a channel to create one!)",
+		))
+		return homeView{Type: "home", Blocks: blocks}
+	}
+
+	// Leave room for a note about any groups that don't fit.
+	const budget = maxHomeBlocks - 1
+	for _, channel := range channels {
+		if len(blocks)+3 > budget {
+			blocks = append(blocks, contextBlock("Some groups didn't fit here. Use the /list flag in each channel to see them all."))
+			break
+		}
+		blocks = append(blocks, sectionBlock("*"+channelMention(channel.ID)+"*"))
+
+		for _, name := range slices.Sorted(maps.Keys(channel.Groups)) {
+			if len(blocks)+2 > budget {
+				break
+			}
+			blocks = append(blocks, homeGroupBlocks(channel.ID, name, channel.Groups[name])...)
+		}
+	}
+	return homeView{Type: "home", Blocks: blocks}
+}
+
+// homeGroupBlocks returns the blocks that show one group in the Home tab.
+func homeGroupBlocks(channelID, name string, group randomizer.Group) []block {
+	heading := "*" + name + "*"
+	if group.Description != "" {
+		heading += " — " + group.Description
+	}
+
+	options := slices.Sorted(slices.Values(group.Options))
+	var message strings.Builder
+	message.WriteString(heading)
+	for i, option := range options {
+		line := "\n• " + option
+		if message.Len()+len(line) > maxSectionTextLength-len("\n• …and 9999 more") {
+			fmt.Fprintf(&message, "\n• …and %d more", len(options)-i)
+			break
+		}
+		message.WriteString(line)
+	}
+
+	blocks := []block{sectionBlock(message.String())}
+	value, err := json.Marshal(homeActionValue{ChannelID: channelID, Group: name})
+	if err != nil || len(value) > maxButtonValueLength {
+		return blocks
+	}
+	return append(blocks, block{
+		Type: "actions",
+		Elements: []any{
+			button{
+				Type:     "button",
+				Text:     text{Type: "plain_text", Text: "Randomize"},
+				ActionID: homeRandomizeActionID,
+				Value:    string(value),
+				Style:    "primary",
+			},
+			button{
+				Type:     "button",
+				Text:     text{Type: "plain_text", Text: "Edit"},
+				ActionID: homeEditActionID,
+				Value:    string(value),
+			},
+		},
+	})
+}
+
+func channelMention(channelID string) string {
+	return "<#" + channelID + ">"
+}
+
+// homeInvocation returns the invocation for a button in the Home tab.
+func homeInvocation(payload interactionPayload, value string) (invocation, string, error) {
+	var target homeActionValue
+	if err := json.Unmarshal([]byte(value), &target); err != nil {
+		return invocation{}, "", fmt.Errorf("decoding Home tab action value: %w", err)
+	}
+	inv := invocation{
+		Command:   defaultCommand,
+		Workspace: payload.workspace(),
+		ChannelID: target.ChannelID,
+		UserID:    payload.User.ID,
+	}
+	return inv, target.Group, nil
+}
+
+// randomizeFromHome randomizes a group for the "Randomize" button in the Home
+// tab, and posts the result in the group's channel.
+func (a App) randomizeFromHome(ctx context.Context, payload interactionPayload, value string) error {
+	inv, group, err := homeInvocation(payload, value)
+	if err != nil {
+		return err
+	}
+
+	inv.Args = []string{group}
+	result, err := a.runRandomizer(ctx, inv)
+	if err != nil {
+		// There's no message to show the error in, so the best we can do is show
+		// the latest groups.
+		a.logErr(err, "Failed to run randomizer")
+		a.publishHome(ctx, inv.Workspace, inv.UserID)
+		return nil
+	}
+
+	text := fmt.Sprintf("%s randomized the %q group. %s", mention(inv.UserID), group, result.Message())
+	return a.Client.PostMessage(withInstallation(ctx, inv.Workspace), Message{Channel: inv.ChannelID, Text: text})
+}
+
+// editFromHome opens the group editor for the "Edit" button in the Home tab.
+func (a App) editFromHome(ctx context.Context, payload interactionPayload, value string) error {
+	inv, group, err := homeInvocation(payload, value)
+	if err != nil {
+		return err
+	}
+
+	inv.Args = []string{"/edit", group}
+	result, err := a.runRandomizer(ctx, inv)
+	if err != nil {
+		a.logErr(err, "Failed to run randomizer")
+		a.publishHome(ctx, inv.Workspace, inv.UserID)
+		return nil
+	}
+	if !a.openEditor(ctx, inv, payload.TriggerID, "", result) {
+		return errors.New("couldn't open group editor")
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func newHomeTestApp(t *testing.T, fake *fakeSlack) App {
+	stores := &rndtest.Partitions{}
+	stores.Store("C1").Put(context.Background(), "lunch", []string{"tacos", "pizza"})
+	stores.Store("C2").Put(context.Background(), "games", []string{"chess", "go"})
+	stores.Store("C3").Put(context.Background(), "hidden", []string{"one", "two"})
+
+	return App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory: func(partition string) randomizer.Store {
+			return stores.Store(partition)
+		},
+		Client:          fake.start(t),
+		RunInBackground: func(task func()) { task() },
+	}
+}
+
+func TestHomeTab(t *testing.T) {
+	fake := &fakeSlack{
+		Token: "xoxb-test",
+		// The randomizer can't see the members of C3.
+		Channels: map[string][]string{"C1": {"U1", "U2"}, "C2": {"U2"}},
+	}
+	app := newHomeTestApp(t, fake)
+
+	body := `{"type":"event_callback","token":"right","team_id":"T1","event":{"type":"app_home_opened","user":"U1","tab":"home"}}`
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	app.EventHandler().ServeHTTP(resp, req)
+
+	if resp.Result().StatusCode != http.StatusOK {
+		t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+	}
+
+	home := fake.Home("U1")
+	for _, want := range []string{`"type":"home"`, "\\u003c#C1\\u003e", "*lunch*", "• pizza", "• tacos", homeRandomizeActionID, homeEditActionID} {
+		if !strings.Contains(home, want) {
+			t.Errorf("Home tab missing %q\n%s", want, home)
+		}
+	}
+	for _, unwanted := range []string{"games", "hidden"} {
+		if strings.Contains(home, unwanted) {
+			t.Errorf("Home tab unexpectedly contains %q\n%s", unwanted, home)
+		}
+	}
+}
+
+func TestHomeTabWithoutGroups(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-test", Channels: map[string][]string{"C1": {"U2"}}}
+	app := newHomeTestApp(t, fake)
+
+	app.publishHome(context.Background(), workspace{TeamID: "T1"}, "U1")
+	if home := fake.Home("U1"); !strings.Contains(home, "none of your channels have any groups") {
+		t.Errorf("unexpected Home tab: %s", home)
+	}
+}
+
+func TestHomeTabChannelLimit(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-test", Channels: make(map[string][]string)}
+	app := newHomeTestApp(t, fake)
+	for i := range 2 * maxHomeChannels {
+		channel := fmt.Sprintf("C%03d", i)
+		fake.Channels[channel] = []string{"U1"}
+		app.StoreFactory(channel).Put(context.Background(), "lunch", []string{"tacos", "pizza"})
+	}
+
+	app.publishHome(context.Background(), workspace{TeamID: "T1"}, "U1")
+	if home := fake.Home("U1"); !strings.Contains(home, "Some groups didn't fit here") {
+		t.Errorf("Home tab doesn't say that groups are missing: %s", home)
+	}
+	var lookups int
+	for _, call := range fake.Calls() {
+		if call == "conversations.members" {
+			lookups++
+		}
+	}
+	if lookups > maxHomeChannels+1 {
+		t.Errorf("looked up the members of %d channels, want at most %d", lookups, maxHomeChannels+1)
+	}
+}
+
+func TestHomeCache(t *testing.T) {
+	now := time.Now()
+	cache := &HomeCache{now: func() time.Time { return now }}
+
+	var loads int
+	load := func() ([]string, error) {
+		loads++
+		return []string{"C1"}, nil
+	}
+	check := func(installation string, wantLoads int) {
+		t.Helper()
+		partitions, err := cache.partitions(installation, load)
+		if err != nil || !slices.Equal(partitions, []string{"C1"}) {
+			t.Fatalf("got %v, %v", partitions, err)
+		}
+		if loads != wantLoads {
+			t.Errorf("got %d loads, want %d", loads, wantLoads)
+		}
+	}
+
+	check("T1", 1)
+	check("T1", 1)
+	check("T2", 2)
+
+	now = now.Add(DefaultHomeCacheTTL)
+	check("T1", 3)
+
+	failing := func() ([]string, error) { return nil, errors.New("store failure") }
+	if _, err := cache.partitions("T3", failing); err == nil {
+		t.Error("unexpected success from failing load")
+	}
+	check("T3", 4)
+}
+
+func TestHomeActions(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-test", Channels: map[string][]string{"C1": {"U1"}}}
+	app := newHomeTestApp(t, fake)
+
+	sendAction := func(actionID string) {
+		payload := map[string]any{
+			"type":       "block_actions",
+			"token":      "right",
+			"trigger_id": "trigger",
+			"user":       map[string]string{"id": "U1"},
+			"team":       map[string]string{"id": "T1"},
+			"actions":    []map[string]string{{"action_id": actionID, "value": `{"channel_id":"C1","group":"lunch"}`}},
+		}
+		payloadJSON, _ := json.Marshal(payload)
+		form := url.Values{"payload": {string(payloadJSON)}}
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		app.InteractionHandler().ServeHTTP(resp, req)
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+		}
+	}
+
+	sendAction(homeRandomizeActionID)
+	messages := fake.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages posted, want 1", len(messages))
+	}
+	if got := messages[0]; got.Channel != "C1" || !strings.Contains(got.Text, `<@U1> randomized the "lunch" group. I randomized and got`) {
+		t.Errorf("unexpected message: %+v", got)
+	}
+
+	sendAction(homeEditActionID)
+	views := fake.Views()
+	if len(views) != 1 {
+		t.Fatalf("got %d views opened, want 1", len(views))
+	}
+	if !strings.Contains(views[0], `pizza\ntacos`) || !strings.Contains(views[0], editorCallbackID) {
+		t.Errorf("unexpected editor view: %s", views[0])
+	}
+}
@@ -0,0 +1,308 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+const (
+	rerollActionID = "reroll"
+	acceptActionID = "accept"
+)
+
+// Slack limits the length of button values and of the text in section
+// blocks. Selections that would exceed these limits are shown without
+// buttons.
+const (
+	maxButtonValueLength = 2000
+	maxSectionTextLength = 3000
+)
+
+// block represents a Block Kit layout block. It includes only the fields that
+// the randomizer uses.
+type block struct {
+	Type     string `json:"type"`
+	BlockID  string `json:"block_id,omitempty"`
+	Text     *text  `json:"text,omitempty"`
+	Label    *text  `json:"label,omitempty"`
+	Element  any    `json:"element,omitempty"`
+	Hint     *text  `json:"hint,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Elements []any  `json:"elements,omitempty"`
+}
+
+// text represents a Block Kit text object.
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// button represents a Block Kit button element.
+type button struct {
+	Type     string `json:"type"`
+	Text     text   `json:"text"`
+	ActionID string `json:"action_id"`
+	Value    string `json:"value,omitempty"`
+	Style    string `json:"style,omitempty"`
+}
+
+// rerollValue is the value of a "Reroll" button, which holds the original
+// invocation that produced the selection.
+type rerollValue struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// selectionBlocks returns the Block Kit blocks that show the message of a
+// selection with "Reroll" and "Accept" buttons, followed by any notes. It
+// returns nil if the selection can't fit in blocks.
+func selectionBlocks(inv invocation, message string, notes ...string) []block {
+	value, err := json.Marshal(rerollValue{Command: inv.Command, Args: inv.Args})
+	if err != nil || len(value) > maxButtonValueLength || len(message) > maxSectionTextLength {
+		return nil
+	}
+
+	blocks := []block{sectionBlock(message)}
+	if len(notes) > 0 {
+		blocks = append(blocks, contextBlock(notes...))
+	}
+	return append(blocks, block{
+		Type: "actions",
+		Elements: []any{
+			button{
+				Type:     "button",
+				Text:     text{Type: "plain_text", Text: "Reroll"},
+				ActionID: rerollActionID,
+				Value:    string(value),
+			},
+			button{
+				Type:     "button",
+				Text:     text{Type: "plain_text", Text: "Accept"},
+				ActionID: acceptActionID,
+				Style:    "primary",
+			},
+		},
+	})
+}
+
+func sectionBlock(message string) block {
+	return block{Type: "section", Text: &text{Type: "mrkdwn", Text: message}}
+}
+
+func contextBlock(notes ...string) block {
+	elements := make([]any, len(notes))
+	for i, note := range notes {
+		elements[i] = text{Type: "mrkdwn", Text: note}
+	}
+	return block{Type: "context", Elements: elements}
+}
+
+// interactionPayload represents the payload of an interaction request. It
+// includes only the fields that the randomizer uses.
+type interactionPayload struct {
+	Type        string `json:"type"`
+	Token       string `json:"token"`
+	CallbackID  string `json:"callback_id"`
+	ResponseURL string `json:"response_url"`
+	TriggerID   string `json:"trigger_id"`
+	User        struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	Enterprise struct {
+		ID string `json:"id"`
+	} `json:"enterprise"`
+	IsEnterpriseInstall bool `json:"is_enterprise_install"`
+	Channel             struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Text string `json:"text"`
+		TS   string `json:"ts"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	View struct {
+		CallbackID      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+		State           struct {
+			Values map[string]map[string]struct {
+				Value string `json:"value"`
+			} `json:"values"`
+		} `json:"state"`
+	} `json:"view"`
+}
+
+func (p interactionPayload) workspace() workspace {
+	return workspace{
+		EnterpriseID:      p.Enterprise.ID,
+		TeamID:            p.Team.ID,
+		EnterpriseInstall: p.IsEnterpriseInstall,
+	}
+}
+
+// InteractionHandler returns a handler for requests from Slack's
+// interactivity API, which Slack sends when users click the buttons on the
+// randomizer's results or in its Home tab, submit the group editor opened by
+// the /edit flag, or use the "Pick a winner from reactions" message shortcut.
+// Configure its URL as the "Request URL" under "Interactivity & Shortcuts" in
+// the Slack app configuration.
+//
+// The handler verifies requests in the same way as App.ServeHTTP, and updates
+// the original messages through their response URLs.
+func (a App) InteractionHandler() http.Handler {
+	return http.HandlerFunc(a.serveInteraction)
+}
+
+func (a App) serveInteraction(w http.ResponseWriter, r *http.Request) {
+	params, _, ok := a.readRequest(w, r, func(params url.Values, _ []byte) string {
+		var payload interactionPayload
+		json.Unmarshal([]byte(params.Get("payload")), &payload)
+		return payload.Token
+	})
+	if !ok {
+		return
+	}
+
+	var payload interactionPayload
+	if err := json.Unmarshal([]byte(params.Get("payload")), &payload); err != nil {
+		a.logErr(err, "Failed to decode interaction payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	body, err := a.handleInteraction(r.Context(), payload)
+	if err != nil {
+		a.logErr(err, "Failed to respond to interaction")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if body != nil {
+		webhook.WriteJSON(w, a.Logger, body)
+	}
+}
+
+// handleInteraction handles a verified interaction, and updates the original
+// message through its response URL. It returns the body of the response to
+// the interaction request itself, which is nil except for view submissions.
+//
+// If the interaction is a retry of one that App already handled,
+// handleInteraction returns the original response without repeating its
+// updates.
+func (a App) handleInteraction(ctx context.Context, payload interactionPayload) (any, error) {
+	body, retried, err := a.RetryCache.do(ctx, retryKey("interaction", payload.TriggerID), func() (any, error) {
+		return a.runInteraction(ctx, payload)
+	})
+	if retried && err == nil {
+		a.logRetry(payload.TriggerID)
+	}
+	return body, err
+}
+
+func (a App) runInteraction(ctx context.Context, payload interactionPayload) (any, error) {
+	// Slack expects a successful response to every interaction, even those that
+	// the randomizer doesn't handle.
+	switch {
+	case payload.Type == "view_submission" && payload.View.CallbackID == editorCallbackID:
+		return a.submitEditor(ctx, payload)
+	case payload.Type == "message_action" && payload.CallbackID == reactionsCallbackID:
+		return nil, a.openReactionPicker(ctx, payload)
+	case payload.Type == "view_submission" && payload.View.CallbackID == reactionsCallbackID:
+		return a.submitReactionPicker(ctx, payload)
+	case payload.Type != "block_actions" || len(payload.Actions) == 0:
+		return nil, nil
+	}
+
+	var resp response
+	switch action := payload.Actions[0]; action.ActionID {
+	case rerollActionID:
+		resp = a.reroll(ctx, payload, action.Value)
+	case acceptActionID:
+		resp = response{
+			Type:            typeInChannel,
+			Text:            payload.Message.Text,
+			Blocks:          []block{sectionBlock(payload.Message.Text), contextBlock("Accepted by " + mention(payload.User.ID))},
+			ReplaceOriginal: true,
+		}
+	case homeRandomizeActionID:
+		return nil, a.randomizeFromHome(ctx, payload, action.Value)
+	case homeEditActionID:
+		return nil, a.editFromHome(ctx, payload, action.Value)
+	default:
+		return nil, nil
+	}
+
+	return nil, a.respond(ctx, payload.ResponseURL, resp)
+}
+
+// reroll runs the randomizer again with the invocation held in the value of a
+// "Reroll" button, and returns a response that replaces the original message.
+func (a App) reroll(ctx context.Context, payload interactionPayload, value string) response {
+	var original rerollValue
+	if err := json.Unmarshal([]byte(value), &original); err != nil {
+		a.logErr(err, "Failed to decode reroll value")
+		return response{
+			Type: typeEphemeral,
+			Text: "Whoops, I couldn't figure out what to reroll. Please run the command again!",
+		}
+	}
+
+	inv := invocation{
+		Command:   original.Command,
+		Workspace: payload.workspace(),
+		ChannelID: payload.Channel.ID,
+		UserID:    payload.User.ID,
+		Args:      original.Args,
+	}
+	result, err := a.runRandomizer(ctx, inv)
+	if err != nil {
+		a.logErr(err, "Failed to run randomizer")
+		return errorResponse(err)
+	}
+
+	resp := a.resultResponse(inv, result)
+	resp.Blocks = selectionBlocks(inv, result.Message(), "Rerolled by "+mention(payload.User.ID))
+	resp.ReplaceOriginal = true
+	return resp
+}
+
+// respond posts a response to a Slack response URL.
+func (a App) respond(ctx context.Context, responseURL string, resp response) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(resp); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("posting to response URL: HTTP status %s", httpResp.Status)
+	}
+	return nil
+}
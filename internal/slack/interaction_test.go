@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestSelectionButtons(t *testing.T) {
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return make(rndtest.Store) },
+	}
+
+	params := makeTestParams("one two three")
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.ServeHTTP(resp, req)
+
+	var body struct {
+		Blocks []struct {
+			Type     string `json:"type"`
+			Elements []struct {
+				ActionID string `json:"action_id"`
+				Value    string `json:"value"`
+			} `json:"elements"`
+		} `json:"blocks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	var value string
+	for _, block := range body.Blocks {
+		for _, element := range block.Elements {
+			if element.ActionID == rerollActionID {
+				value = element.Value
+			}
+		}
+	}
+	want := `{"command":"/randomize","args":["one","two","three"]}`
+	if value != want {
+		t.Errorf("got reroll value %q, want %q", value, want)
+	}
+}
+
+func TestInteractions(t *testing.T) {
+	testCases := []struct {
+		description string
+		actionID    string
+		value       string
+		contains    []string
+	}{
+		{
+			description: "rerolling a selection",
+			actionID:    rerollActionID,
+			value:       `{"command":"/randomize","args":["test"]}`,
+			contains:    []string{`"replace_original":true`, "I randomized and got", "*one*", "Rerolled by <@U2>", rerollActionID},
+		},
+		{
+			description: "rerolling a group that no longer exists",
+			actionID:    rerollActionID,
+			value:       `{"command":"/randomize","args":["missing"]}`,
+			contains:    []string{`"response_type":"ephemeral"`, "couldn't find the \\\"missing\\\" group"},
+		},
+		{
+			description: "accepting a selection",
+			actionID:    acceptActionID,
+			contains:    []string{`"replace_original":true`, "I randomized and got: *two*, *one*.", "Accepted by <@U2>"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			responses := make(chan string, 1)
+			responseSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				responses <- string(body)
+			}))
+			defer responseSrv.Close()
+
+			app := App{
+				TokenProvider: StaticToken("right"),
+				StoreFactory: func(_ string) randomizer.Store {
+					return rndtest.Store{"test": {"one", "two"}}
+				},
+				HTTPClient: responseSrv.Client(),
+			}
+
+			payload := map[string]any{
+				"type":         "block_actions",
+				"token":        "right",
+				"response_url": responseSrv.URL,
+				"user":         map[string]string{"id": "U2"},
+				"channel":      map[string]string{"id": "C12345678"},
+				"message":      map[string]string{"text": "I randomized and got: *two*, *one*."},
+				"actions":      []map[string]string{{"action_id": tc.actionID, "value": tc.value}},
+			}
+			payloadJSON, _ := json.Marshal(payload)
+			form := url.Values{"payload": {string(payloadJSON)}}
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			app.InteractionHandler().ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != http.StatusOK {
+				t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+			}
+
+			select {
+			case body := <-responses:
+				for _, c := range tc.contains {
+					if !strings.Contains(body, c) {
+						t.Errorf("response missing %q\n%s", c, body)
+					}
+				}
+			default:
+				t.Fatal("no response posted to response URL")
+			}
+		})
+	}
+}
+
+func TestInteractionInvalidToken(t *testing.T) {
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return rndtest.Store(nil) },
+	}
+
+	form := url.Values{"payload": {`{"type":"block_actions","token":"wrong"}`}}
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.InteractionHandler().ServeHTTP(resp, req)
+
+	if resp.Result().StatusCode != http.StatusForbidden {
+		t.Errorf("wrong status for invalid token: got %v, want %v", resp.Result().StatusCode, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,341 @@
+package slack
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// AuthorizeURL is the URL of the page on which users install Slack apps into
+// their workspaces.
+const AuthorizeURL = "https://slack.com/oauth/v2/authorize"
+
+// DefaultScopes are the bot token scopes that the randomizer requests when a
+// workspace installs it, which cover all of the randomizer's features.
+var DefaultScopes = []string{
+	"commands",
+	"usergroups:read",
+	"channels:read",
+	"groups:read",
+	"app_mentions:read",
+	"chat:write",
+	"reactions:read",
+}
+
+// OAuthConfig configures the installation of the randomizer into multiple
+// Slack workspaces through Slack's OAuth v2 flow. Find these settings on the
+// "Basic Information" and "OAuth & Permissions" pages of the Slack app
+// configuration.
+type OAuthConfig struct {
+	// ClientID is the app's client ID.
+	ClientID string
+	// ClientSecret provides the app's client secret.
+	ClientSecret func(ctx context.Context) (string, error)
+	// Scopes are the bot token scopes to request. If empty, the randomizer
+	// requests DefaultScopes.
+	Scopes []string
+	// RedirectURL, if non-empty, is the URL of App.OAuthRedirectHandler, which
+	// must match one of the redirect URLs in the Slack app configuration. If
+	// empty, Slack uses the first redirect URL in the configuration.
+	RedirectURL string
+	// BaseURL, if non-empty, overrides DefaultBaseURL for calls to the Web API
+	// during installation. It must end with a slash.
+	BaseURL string
+}
+
+// OAuthConfigFromEnv returns an OAuthConfig based on available environment
+// variables.
+//
+// If SLACK_CLIENT_ID is set, it returns a config with that client ID, and with
+// the client secret from SLACK_CLIENT_SECRET, or from the AWS SSM parameter
+// named by SLACK_CLIENT_SECRET_SSM_NAME (cached for SLACK_TOKEN_SSM_TTL). It
+// reads the optional scopes from SLACK_OAUTH_SCOPES as a comma-separated list,
+// and the optional redirect URL from SLACK_OAUTH_REDIRECT_URL.
+//
+// Otherwise, it returns a nil config and a nil error, as the randomizer can
+// serve a single workspace without OAuth.
+func OAuthConfigFromEnv() (*OAuthConfig, error) {
+	clientID, ok := os.LookupEnv("SLACK_CLIENT_ID")
+	if !ok {
+		return nil, nil
+	}
+
+	config := &OAuthConfig{
+		ClientID:    clientID,
+		RedirectURL: os.Getenv("SLACK_OAUTH_REDIRECT_URL"),
+	}
+
+	if secret, ok := os.LookupEnv("SLACK_CLIENT_SECRET"); ok {
+		config.ClientSecret = func(_ context.Context) (string, error) { return secret, nil }
+	} else if ssmName, ok := os.LookupEnv("SLACK_CLIENT_SECRET_SSM_NAME"); ok {
+		ttl, err := ssmTTLFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		config.ClientSecret = ssmParameter(ssmName, ttl)
+	} else {
+		return nil, errors.New("missing SLACK_CLIENT_SECRET or SLACK_CLIENT_SECRET_SSM_NAME for SLACK_CLIENT_ID")
+	}
+
+	if scopes, ok := os.LookupEnv("SLACK_OAUTH_SCOPES"); ok {
+		config.Scopes = strings.Split(scopes, ",")
+	}
+
+	return config, nil
+}
+
+// BotTokenStore is implemented by stores that can hold the bot token that a
+// Slack workspace granted when it installed the randomizer. App saves each
+// workspace's token in the store for a partition dedicated to that
+// installation, separate from the partitions of the workspace's channels.
+type BotTokenStore interface {
+	// GetBotToken returns the saved bot token. If no token has been saved, it
+	// returns an empty string with a nil error.
+	GetBotToken(ctx context.Context) (token string, err error)
+
+	// PutBotToken saves a bot token, overwriting any previous token.
+	PutBotToken(ctx context.Context, token string) error
+
+	// DeleteBotToken ensures that no bot token is saved, and indicates whether
+	// one was saved prior to this deletion.
+	DeleteBotToken(ctx context.Context) (existed bool, err error)
+}
+
+// workspace identifies the Slack workspace, and the Enterprise Grid
+// organization if any, that a request came from.
+type workspace struct {
+	EnterpriseID      string
+	TeamID            string
+	EnterpriseInstall bool
+}
+
+// installation returns the partition that holds the bot token for the
+// randomizer's installation in the workspace, which covers the whole
+// organization for an organization-wide installation in Enterprise Grid.
+func (w workspace) installation() string {
+	if w.EnterpriseInstall {
+		return w.EnterpriseID
+	}
+	return joinPartition(w.EnterpriseID, w.TeamID)
+}
+
+// partition returns the partition for the groups of a channel. An App with an
+// OAuthConfig namespaces its partitions by workspace; otherwise, App keeps
+// using bare channel IDs, as it did before it supported multiple workspaces.
+func (a App) partition(w workspace, channelID string) string {
+	if a.OAuth == nil {
+		return channelID
+	}
+	return joinPartition(w.EnterpriseID, w.TeamID, channelID)
+}
+
+func joinPartition(ids ...string) string {
+	var parts []string
+	for _, id := range ids {
+		if id != "" {
+			parts = append(parts, id)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+type installationKey struct{}
+
+// withInstallation returns a context that carries the partition of the
+// installation that a request came from, for use by installedBotToken.
+func withInstallation(ctx context.Context, w workspace) context.Context {
+	return context.WithValue(ctx, installationKey{}, w.installation())
+}
+
+// installedBotToken returns a TokenProvider that loads the bot token of the
+// installation carried by its context.
+func installedBotToken(storeFactory func(partition string) randomizer.Store) TokenProvider {
+	return func(ctx context.Context) (string, error) {
+		partition, _ := ctx.Value(installationKey{}).(string)
+		if partition == "" {
+			return "", errors.New("no Slack installation for request")
+		}
+
+		store, ok := storeFactory(partition).(BotTokenStore)
+		if !ok {
+			return "", errors.New("store can't hold bot tokens")
+		}
+		token, err := store.GetBotToken(ctx)
+		if err != nil {
+			return "", err
+		}
+		if token == "" {
+			return "", fmt.Errorf("no bot token saved for %q", partition)
+		}
+		return token, nil
+	}
+}
+
+// stateCookieName is the name of the cookie that ties each OAuth redirect to
+// the browser that started the installation, to protect against forged
+// installation requests.
+const stateCookieName = "randomizer_oauth_state"
+
+// InstallHandler returns a handler that starts the installation of the
+// randomizer into a Slack workspace, by redirecting the user to Slack to
+// authorize it. Share its URL with the users who should be able to install the
+// randomizer. App.OAuth must be non-nil.
+func (a App) InstallHandler() http.Handler {
+	return http.HandlerFunc(a.serveInstall)
+}
+
+func (a App) serveInstall(w http.ResponseWriter, r *http.Request) {
+	stateBytes := make([]byte, 16)
+	rand.Read(stateBytes)
+	state := base64.RawURLEncoding.EncodeToString(stateBytes)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   600,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	scopes := a.OAuth.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	params := url.Values{
+		"client_id": {a.OAuth.ClientID},
+		"scope":     {strings.Join(scopes, ",")},
+		"state":     {state},
+	}
+	if a.OAuth.RedirectURL != "" {
+		params.Set("redirect_uri", a.OAuth.RedirectURL)
+	}
+	http.Redirect(w, r, AuthorizeURL+"?"+params.Encode(), http.StatusFound)
+}
+
+// OAuthRedirectHandler returns a handler that completes the installation of
+// the randomizer into a Slack workspace, by saving the bot token that the
+// workspace granted. Configure its URL as a "Redirect URL" on the "OAuth &
+// Permissions" page of the Slack app configuration. App.OAuth must be non-nil,
+// and App.StoreFactory must return stores that implement BotTokenStore.
+func (a App) OAuthRedirectHandler() http.Handler {
+	return http.HandlerFunc(a.serveOAuthRedirect)
+}
+
+func (a App) serveOAuthRedirect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(query.Get("state"))) != 1 {
+		writeText(w, http.StatusBadRequest, "Whoops, this installation link has expired or wasn't meant for this browser. Please start the installation again!")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: stateCookieName, Path: "/", MaxAge: -1})
+
+	if query.Get("error") != "" {
+		writeText(w, http.StatusOK, "The randomizer wasn't installed. You can close this window.")
+		return
+	}
+
+	inst, err := a.exchangeCode(r.Context(), query.Get("code"))
+	if err != nil {
+		a.logErr(err, "Failed to complete OAuth installation")
+		writeText(w, http.StatusInternalServerError, "Whoops, I couldn't finish installing the randomizer. Please try again!")
+		return
+	}
+
+	ws := workspace{
+		EnterpriseID:      inst.Enterprise.ID,
+		TeamID:            inst.Team.ID,
+		EnterpriseInstall: inst.IsEnterpriseInstall,
+	}
+	store, ok := a.StoreFactory(ws.installation()).(BotTokenStore)
+	if !ok {
+		a.logErr(errors.New("store can't hold bot tokens"), "Failed to complete OAuth installation")
+		writeText(w, http.StatusInternalServerError, "Whoops, I couldn't finish installing the randomizer. Please try again!")
+		return
+	}
+	if err := store.PutBotToken(r.Context(), inst.AccessToken); err != nil {
+		a.logErr(err, "Failed to save bot token")
+		writeText(w, http.StatusInternalServerError, "Whoops, I couldn't finish installing the randomizer. Please try again!")
+		return
+	}
+
+	name := inst.Team.Name
+	if inst.IsEnterpriseInstall {
+		name = inst.Enterprise.Name
+	}
+	writeText(w, http.StatusOK, fmt.Sprintf("The randomizer is installed in %s! You can close this window.", name))
+}
+
+// oauthAccessResponse represents the response of the oauth.v2.access method.
+// It includes only the fields that the randomizer uses.
+type oauthAccessResponse struct {
+	apiResponse
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Team        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+	Enterprise struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"enterprise"`
+	IsEnterpriseInstall bool `json:"is_enterprise_install"`
+}
+
+// exchangeCode exchanges the temporary code from an OAuth redirect for a bot
+// token with the oauth.v2.access method.
+func (a App) exchangeCode(ctx context.Context, code string) (oauthAccessResponse, error) {
+	secret, err := a.OAuth.ClientSecret(ctx)
+	if err != nil {
+		return oauthAccessResponse{}, fmt.Errorf("loading Slack client secret: %w", err)
+	}
+
+	params := url.Values{
+		"client_id":     {a.OAuth.ClientID},
+		"client_secret": {secret},
+		"code":          {code},
+	}
+	if a.OAuth.RedirectURL != "" {
+		params.Set("redirect_uri", a.OAuth.RedirectURL)
+	}
+
+	var response oauthAccessResponse
+	client := WebClient{BaseURL: a.OAuth.BaseURL, HTTPClient: a.HTTPClient}
+	if err := client.post(ctx, "oauth.v2.access", params, "", &response); err != nil {
+		return oauthAccessResponse{}, err
+	}
+	if response.TokenType != "bot" || response.AccessToken == "" {
+		return oauthAccessResponse{}, fmt.Errorf("got %q token from oauth.v2.access, want bot token", response.TokenType)
+	}
+	return response, nil
+}
+
+// uninstall deletes the bot token of an installation, after Slack reports that
+// a workspace uninstalled the randomizer or revoked its token.
+func (a App) uninstall(ctx context.Context, w workspace) error {
+	store, ok := a.StoreFactory(w.installation()).(BotTokenStore)
+	if !ok {
+		return nil
+	}
+	_, err := store.DeleteBotToken(ctx)
+	return err
+}
+
+func writeText(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintln(w, text)
+}
@@ -0,0 +1,259 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func newOAuthTestApp(t *testing.T, fake *fakeSlack, stores *rndtest.Partitions) App {
+	client := fake.start(t)
+	factory := func(partition string) randomizer.Store { return stores.Store(partition) }
+	return App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  factory,
+		OAuth: &OAuthConfig{
+			ClientID:     "client",
+			ClientSecret: func(_ context.Context) (string, error) { return "secret", nil },
+			BaseURL:      client.BaseURL,
+		},
+		Client:          WebClient{TokenProvider: installedBotToken(factory), BaseURL: client.BaseURL, HTTPClient: client.HTTPClient},
+		HTTPClient:      client.HTTPClient,
+		RunInBackground: func(task func()) { task() },
+	}
+}
+
+func TestOAuthInstall(t *testing.T) {
+	app := newOAuthTestApp(t, &fakeSlack{}, &rndtest.Partitions{})
+
+	resp := httptest.NewRecorder()
+	app.InstallHandler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/install", nil))
+
+	if resp.Result().StatusCode != http.StatusFound {
+		t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(resp.Result().Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect location: %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != AuthorizeURL {
+		t.Errorf("redirected to %s, want %s", got, AuthorizeURL)
+	}
+
+	query := location.Query()
+	if got := query.Get("client_id"); got != "client" {
+		t.Errorf("got client_id %q, want %q", got, "client")
+	}
+	if got, want := query.Get("scope"), strings.Join(DefaultScopes, ","); got != want {
+		t.Errorf("got scope %q, want %q", got, want)
+	}
+
+	var state string
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == stateCookieName {
+			state = cookie.Value
+		}
+	}
+	if state == "" || query.Get("state") != state {
+		t.Errorf("state %q doesn't match cookie %q", query.Get("state"), state)
+	}
+}
+
+func TestOAuthRedirect(t *testing.T) {
+	testCases := []struct {
+		description string
+		query       string
+		cookie      string
+		wantStatus  int
+		wantToken   string
+	}{
+		{
+			description: "successful installation",
+			query:       "code=good&state=abc",
+			cookie:      "abc",
+			wantStatus:  http.StatusOK,
+			wantToken:   "xoxb-installed",
+		},
+		{
+			description: "mismatched state",
+			query:       "code=good&state=abc",
+			cookie:      "xyz",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "missing state cookie",
+			query:       "code=good&state=abc",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			description: "canceled installation",
+			query:       "error=access_denied&state=abc",
+			cookie:      "abc",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "invalid code",
+			query:       "code=bad&state=abc",
+			cookie:      "abc",
+			wantStatus:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			fake := &fakeSlack{Token: "xoxb-installed", OAuthCode: "good"}
+			stores := &rndtest.Partitions{}
+			app := newOAuthTestApp(t, fake, stores)
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/redirect?"+tc.query, nil)
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: stateCookieName, Value: tc.cookie})
+			}
+			resp := httptest.NewRecorder()
+			app.OAuthRedirectHandler().ServeHTTP(resp, req)
+
+			if resp.Result().StatusCode != tc.wantStatus {
+				t.Errorf("invalid status: got %v, want %v\n%s", resp.Result().StatusCode, tc.wantStatus, resp.Body)
+			}
+
+			store := stores.Store("T1")
+			token, _ := store.GetBotToken(context.Background())
+			if token != tc.wantToken {
+				t.Errorf("got saved token %q, want %q", token, tc.wantToken)
+			}
+		})
+	}
+}
+
+func TestWorkspacePartitions(t *testing.T) {
+	testCases := []struct {
+		description   string
+		oauth         bool
+		params        url.Values
+		wantPartition string
+	}{
+		{
+			description:   "single workspace",
+			params:        url.Values{"team_id": {"T1"}},
+			wantPartition: "C12345678",
+		},
+		{
+			description:   "multiple workspaces",
+			oauth:         true,
+			params:        url.Values{"team_id": {"T1"}},
+			wantPartition: "T1:C12345678",
+		},
+		{
+			description:   "enterprise grid",
+			oauth:         true,
+			params:        url.Values{"team_id": {"T1"}, "enterprise_id": {"E1"}},
+			wantPartition: "E1:T1:C12345678",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			stores := &rndtest.Partitions{}
+			app := newOAuthTestApp(t, &fakeSlack{}, stores)
+			if !tc.oauth {
+				app.OAuth = nil
+			}
+
+			params := makeTestParams("/save test one two")
+			for key, values := range tc.params {
+				params[key] = values
+			}
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			app.ServeHTTP(resp, req)
+
+			if _, ok := stores.Store(tc.wantPartition).Store["test"]; !ok {
+				partitions, _ := stores.Store(tc.wantPartition).ListPartitions(context.Background())
+				t.Errorf("group not saved in partition %q; got partitions %v", tc.wantPartition, partitions)
+			}
+		})
+	}
+}
+
+func TestInstalledBotToken(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-installed"}
+	stores := &rndtest.Partitions{}
+	app := newOAuthTestApp(t, fake, stores)
+
+	ctx := context.Background()
+	stores.Store("T1").PutBotToken(ctx, "xoxb-installed")
+
+	sendEvent := func(body string) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		app.EventHandler().ServeHTTP(resp, req)
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+		}
+	}
+
+	sendEvent(`{"type":"event_callback","token":"right","team_id":"T1","event":{"type":"app_mention","user":"U2","channel":"C1","text":"<@U0BOT> one two","ts":"1.1"}}`)
+	if messages := fake.Messages(); len(messages) != 1 {
+		t.Errorf("got %d messages posted with installed token, want 1", len(messages))
+	}
+
+	sendEvent(`{"type":"event_callback","token":"right","team_id":"T1","event":{"type":"app_uninstalled"}}`)
+	token, err := stores.Store("T1").GetBotToken(ctx)
+	if err != nil || token != "" {
+		t.Errorf("GetBotToken() after uninstall = %q, %v", token, err)
+	}
+
+	sendEvent(`{"type":"event_callback","token":"right","team_id":"T1","event":{"type":"app_mention","user":"U2","channel":"C1","text":"<@U0BOT> one two","ts":"1.2"}}`)
+	if messages := fake.Messages(); len(messages) != 1 {
+		t.Errorf("got %d messages posted after uninstall, want 1", len(messages))
+	}
+}
+
+// webAPIScopes lists the bot token scopes that each Web API method used by
+// WebClient requires. Methods that don't need any particular scope map to an
+// empty list.
+var webAPIScopes = map[string][]string{
+	"usergroups.users.list": {"usergroups:read"},
+	"conversations.members": {"channels:read", "groups:read"},
+	"chat.postMessage":      {"chat:write"},
+	"views.open":            {},
+	"views.publish":         {},
+	"reactions.get":         {"reactions:read"},
+}
+
+func TestDefaultScopesCoverWebAPI(t *testing.T) {
+	source, err := os.ReadFile("webapi.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := regexp.MustCompile(`c\.call\(ctx, "([^"]+)"`).FindAllStringSubmatch(string(source), -1)
+	if len(calls) == 0 {
+		t.Fatal("found no Web API calls in webapi.go")
+	}
+
+	for _, call := range calls {
+		method := call[1]
+		scopes, ok := webAPIScopes[method]
+		if !ok {
+			t.Errorf("WebClient calls %s, but the test doesn't know its scopes", method)
+			continue
+		}
+		for _, scope := range scopes {
+			if !slices.Contains(DefaultScopes, scope) {
+				t.Errorf("WebClient calls %s, but DefaultScopes is missing %q", method, scope)
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// RateLimit is a token bucket limit on the rate of requests. It allows bursts
+// of up to Requests requests, and refills at a rate of Requests per Per.
+//
+// The zero value of RateLimit places no limit on requests.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseRateLimit parses a RateLimit of the form "REQUESTS/DURATION", where
+// DURATION is a Go duration, as in "20/1m".
+func ParseRateLimit(s string) (RateLimit, error) {
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("rate limit %q is not of the form REQUESTS/DURATION", s)
+	}
+
+	var (
+		limit RateLimit
+		err   error
+	)
+	limit.Requests, err = strconv.Atoi(requests)
+	if err != nil || limit.Requests < 1 {
+		return RateLimit{}, fmt.Errorf("rate limit %q does not have a positive number of requests", s)
+	}
+	limit.Per, err = time.ParseDuration(per)
+	if err != nil || limit.Per <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q does not have a positive Go duration", s)
+	}
+	return limit, nil
+}
+
+func (l RateLimit) unlimited() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// bucket is the state of a token bucket: the number of tokens it held at a
+// point in time. A bucket with a zero time is full.
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// take refills a bucket for the time elapsed up to now, and takes a token
+// from it if possible. It returns the new state of the bucket, and whether it
+// took a token.
+func (l RateLimit) take(b bucket, now time.Time) (bucket, bool) {
+	// Another instance with a slightly faster clock may have counted the tokens
+	// "after" now, in which case we leave them as they are.
+	capacity := float64(l.Requests)
+	if b.at.IsZero() {
+		b = bucket{tokens: capacity, at: now}
+	} else if elapsed := now.Sub(b.at); elapsed > 0 {
+		b = bucket{tokens: min(capacity, b.tokens+capacity*elapsed.Seconds()/l.Per.Seconds()), at: now}
+	}
+
+	if b.tokens < 1 {
+		return b, false
+	}
+	b.tokens--
+	return b, true
+}
+
+// RateLimitStore is implemented by stores that can hold the state of rate
+// limits, so that multiple instances of the randomizer sharing the same
+// database can enforce the same limits.
+//
+// Stores must preserve the times that they hold to at least millisecond
+// precision.
+type RateLimitStore interface {
+	// GetRateLimit returns the number of tokens in the named bucket, and the
+	// time at which they were counted. If the bucket does not exist, it returns
+	// a zero time with a nil error.
+	GetRateLimit(ctx context.Context, key string) (tokens float64, at time.Time, err error)
+
+	// PutRateLimit saves the number of tokens in the named bucket, and the time
+	// at which they were counted, but only if the bucket's time still matches
+	// prevAt (or if the bucket doesn't exist, for a zero prevAt). It reports
+	// whether it saved the bucket.
+	//
+	// The bucket refills completely by the expires time, after which it's the
+	// same as a bucket that doesn't exist. Stores should delete it some time
+	// after then, so that they don't keep every user and channel forever.
+	PutRateLimit(ctx context.Context, key string, tokens float64, at, prevAt, expires time.Time) (saved bool, err error)
+}
+
+// maxRateLimitAttempts limits the number of times that a shared RateLimiter
+// tries to update a bucket that other instances are updating at the same time.
+const maxRateLimitAttempts = 5
+
+// RateLimiter limits the rate at which each user can run the randomizer, and
+// the rate at which it runs in each channel.
+type RateLimiter struct {
+	// PerUser and PerChannel set the limits for each user and each channel.
+	PerUser    RateLimit
+	PerChannel RateLimit
+	// Shared, if true, keeps the state of the limits in the store for the
+	// randomizer's installation in each workspace, which must implement
+	// RateLimitStore. Otherwise, the RateLimiter keeps the state in memory,
+	// so each instance of the randomizer enforces its own limits.
+	Shared bool
+
+	mu      sync.Mutex
+	buckets map[string]bucket
+	swept   time.Time
+	now     func() time.Time // Overridden in tests for predictable behavior
+}
+
+// RateLimiterFromEnv returns a RateLimiter based on available environment
+// variables.
+//
+// SLACK_RATE_LIMIT_USER and SLACK_RATE_LIMIT_CHANNEL set the limits for each
+// user and each channel, in the form accepted by ParseRateLimit.
+//
+// SLACK_RATE_LIMIT_SHARED, if set to a true value as accepted by
+// strconv.ParseBool, keeps the state of the limits in the store.
+//
+// If neither limit is set, RateLimiterFromEnv returns a nil RateLimiter and a
+// nil error.
+func RateLimiterFromEnv() (*RateLimiter, error) {
+	var limiter RateLimiter
+	limits := []struct {
+		key   string
+		value *RateLimit
+	}{
+		{"SLACK_RATE_LIMIT_USER", &limiter.PerUser},
+		{"SLACK_RATE_LIMIT_CHANNEL", &limiter.PerChannel},
+	}
+	for _, limit := range limits {
+		env, ok := os.LookupEnv(limit.key)
+		if !ok {
+			continue
+		}
+		var err error
+		*limit.value, err = ParseRateLimit(env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", limit.key, err)
+		}
+	}
+
+	if limiter.PerUser.unlimited() && limiter.PerChannel.unlimited() {
+		return nil, nil
+	}
+
+	if env, ok := os.LookupEnv("SLACK_RATE_LIMIT_SHARED"); ok {
+		var err error
+		limiter.Shared, err = strconv.ParseBool(env)
+		if err != nil {
+			return nil, fmt.Errorf("SLACK_RATE_LIMIT_SHARED is not a valid boolean: %w", err)
+		}
+	}
+
+	return &limiter, nil
+}
+
+// rateLimitError is returned by App.runRandomizer for invocations that exceed
+// a rate limit.
+type rateLimitError struct {
+	scope string
+}
+
+func (e rateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded", e.scope)
+}
+
+// HelpText implements the same method as randomizer.Error.
+func (e rateLimitError) HelpText() string {
+	if e.scope == "channel" {
+		return "Whoops, I'm getting a lot of requests in this channel! Please slow down and try again in a little while."
+	}
+	return "Whoops, you're sending me a lot of requests! Please slow down and try again in a little while."
+}
+
+// checkRateLimit returns a rateLimitError if an invocation exceeds a rate
+// limit. If it can't load or save the state of the limits, it logs the error
+// and allows the invocation, so that a failing store doesn't lock users out.
+func (a App) checkRateLimit(ctx context.Context, inv invocation) error {
+	r := a.RateLimiter
+	if r == nil {
+		return nil
+	}
+
+	// The user and channel IDs in a workspace are unique within the
+	// installation, so that's where we track their limits. Slack sends the
+	// workspace with every request, but if it's missing we can only track them
+	// within the channel.
+	partition := inv.Workspace.installation()
+	if partition == "" {
+		partition = a.partition(inv.Workspace, inv.ChannelID)
+	}
+
+	checks := []struct {
+		scope string
+		id    string
+		limit RateLimit
+	}{
+		{"user", inv.UserID, r.PerUser},
+		{"channel", inv.ChannelID, r.PerChannel},
+	}
+	for _, check := range checks {
+		if check.id == "" || check.limit.unlimited() {
+			continue
+		}
+		key := check.scope + ":" + check.id
+		ok, err := r.take(ctx, a.StoreFactory, partition, key, check.limit)
+		if err != nil {
+			a.logErr(err, "Failed to check rate limit", "key", key)
+			continue
+		}
+		if !ok {
+			return rateLimitError{scope: check.scope}
+		}
+	}
+	return nil
+}
+
+func (r *RateLimiter) take(ctx context.Context, storeFactory func(string) randomizer.Store, partition, key string, limit RateLimit) (bool, error) {
+	nowFunc := r.now
+	if nowFunc == nil {
+		nowFunc = time.Now
+	}
+	// Truncating the time keeps it the same after a round trip through a store.
+	now := nowFunc().UTC().Truncate(time.Millisecond)
+
+	if !r.Shared {
+		return r.takeLocal(partition+"/"+key, limit, now), nil
+	}
+
+	store, ok := storeFactory(partition).(RateLimitStore)
+	if !ok {
+		return false, errors.New("store can't hold rate limits")
+	}
+	for range maxRateLimitAttempts {
+		tokens, at, err := store.GetRateLimit(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		next, ok := limit.take(bucket{tokens: tokens, at: at}, now)
+		if !ok {
+			return false, nil
+		}
+		saved, err := store.PutRateLimit(ctx, key, next.tokens, next.at, at, next.at.Add(limit.Per))
+		if err != nil {
+			return false, err
+		}
+		if saved {
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("bucket %q changed %d times while updating", key, maxRateLimitAttempts)
+}
+
+func (r *RateLimiter) takeLocal(key string, limit RateLimit, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.buckets == nil {
+		r.buckets = make(map[string]bucket)
+	}
+	next, ok := limit.take(r.buckets[key], now)
+	r.buckets[key] = next
+
+	// Every bucket refills completely within the longest period, and full
+	// buckets are the same as missing ones, so we can forget them. Sweeping at
+	// most once per period bounds the map without scanning it on every request.
+	maxPer := max(r.PerUser.Per, r.PerChannel.Per)
+	if now.Sub(r.swept) >= maxPer {
+		for k, b := range r.buckets {
+			if now.Sub(b.at) >= maxPer {
+				delete(r.buckets, k)
+			}
+		}
+		r.swept = now
+	}
+	return ok
+}
@@ -0,0 +1,182 @@
+package slack
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{input: "20/1m", want: RateLimit{Requests: 20, Per: time.Minute}},
+		{input: "1/30s", want: RateLimit{Requests: 1, Per: 30 * time.Second}},
+		{input: "20", wantErr: true},
+		{input: "0/1m", wantErr: true},
+		{input: "-1/1m", wantErr: true},
+		{input: "twenty/1m", wantErr: true},
+		{input: "20/0s", wantErr: true},
+		{input: "20/minute", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseRateLimit(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	testCases := []struct {
+		description string
+		limiter     *RateLimiter
+		requests    []string // "USER CHANNEL", or "+DURATION" to advance time
+		wantHelp    []string // "" for an allowed request
+	}{
+		{
+			description: "per user",
+			limiter:     &RateLimiter{PerUser: RateLimit{Requests: 2, Per: time.Minute}},
+			requests:    []string{"U1 C1", "U1 C2", "U1 C1", "U2 C1", "+30s", "U1 C1", "U1 C1"},
+			wantHelp:    []string{"", "", "you're sending me a lot of requests", "", "", "you're sending me a lot of requests"},
+		},
+		{
+			description: "per channel",
+			limiter:     &RateLimiter{PerChannel: RateLimit{Requests: 2, Per: time.Minute}},
+			requests:    []string{"U1 C1", "U2 C1", "U3 C1", "U3 C2", "+1m", "U1 C1", "U1 C1", "U1 C1"},
+			wantHelp:    []string{"", "", "lot of requests in this channel", "", "", "", "lot of requests in this channel"},
+		},
+		{
+			description: "shared",
+			limiter:     &RateLimiter{PerUser: RateLimit{Requests: 2, Per: time.Minute}, Shared: true},
+			requests:    []string{"U1 C1", "U1 C1", "U1 C1", "+30s", "U1 C1", "U1 C1"},
+			wantHelp:    []string{"", "", "you're sending me a lot of requests", "", "you're sending me a lot of requests"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			tc.limiter.now = func() time.Time { return now }
+
+			store := rndtest.Store{"test": {"one", "two"}}
+			app := App{
+				TokenProvider: StaticToken("right"),
+				StoreFactory:  func(_ string) randomizer.Store { return store },
+				RateLimiter:   tc.limiter,
+			}
+
+			var gotHelp []string
+			for _, request := range tc.requests {
+				if d, ok := strings.CutPrefix(request, "+"); ok {
+					duration, _ := time.ParseDuration(d)
+					now = now.Add(duration)
+					continue
+				}
+
+				user, channel, _ := strings.Cut(request, " ")
+				params := makeTestParams("test")
+				params.Set("team_id", "T1")
+				params.Set("user_id", user)
+				params.Set("channel_id", channel)
+				resp := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				app.ServeHTTP(resp, req)
+
+				if resp.Result().StatusCode != http.StatusOK {
+					t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+				}
+				body := resp.Body.String()
+				switch {
+				case strings.Contains(body, `"response_type":"in_channel"`):
+					gotHelp = append(gotHelp, "")
+				case strings.Contains(body, `"response_type":"ephemeral"`) && strings.Contains(body, "slow down"):
+					_, help, _ := strings.Cut(body, "Whoops, ")
+					gotHelp = append(gotHelp, help)
+				default:
+					t.Fatalf("unexpected response: %s", body)
+				}
+			}
+
+			if len(gotHelp) != len(tc.wantHelp) {
+				t.Fatalf("got %d responses, want %d", len(gotHelp), len(tc.wantHelp))
+			}
+			for i := range gotHelp {
+				if !strings.Contains(gotHelp[i], tc.wantHelp[i]) || (tc.wantHelp[i] == "") != (gotHelp[i] == "") {
+					t.Errorf("response %d: got %q, want one containing %q", i, gotHelp[i], tc.wantHelp[i])
+				}
+			}
+
+			_, hasShared := store["/ratelimit/user:U1"]
+			if hasShared != tc.limiter.Shared {
+				t.Errorf("store has shared limit: %v, want %v", hasShared, tc.limiter.Shared)
+			}
+		})
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	var (
+		start   = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		limit   = RateLimit{Requests: 1, Per: time.Minute}
+		limiter = &RateLimiter{PerUser: limit}
+	)
+	steps := []struct {
+		after time.Duration
+		key   string
+		want  []string
+	}{
+		{0, "a", []string{"a"}},
+		{30 * time.Second, "b", []string{"a", "b"}},
+		{61 * time.Second, "c", []string{"b", "c"}},      // Sweeps "a".
+		{95 * time.Second, "d", []string{"b", "c", "d"}}, // Too soon to sweep "b".
+		{122 * time.Second, "e", []string{"d", "e"}},     // Sweeps "b" and "c".
+	}
+	for _, step := range steps {
+		limiter.takeLocal(step.key, limit, start.Add(step.after))
+		if got := slices.Sorted(maps.Keys(limiter.buckets)); !slices.Equal(got, step.want) {
+			t.Errorf("after %v: got buckets %v, want %v", step.after, got, step.want)
+		}
+	}
+}
+
+func TestRateLimitStoreFailure(t *testing.T) {
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory: func(_ string) randomizer.Store {
+			// Wrapping the store hides its RateLimitStore implementation.
+			return struct{ randomizer.Store }{rndtest.Store{"test": {"one", "two"}}}
+		},
+		RateLimiter: &RateLimiter{PerUser: RateLimit{Requests: 1, Per: time.Hour}, Shared: true},
+	}
+
+	for range 3 {
+		params := makeTestParams("test")
+		params.Set("team_id", "T1")
+		params.Set("user_id", "U1")
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		app.ServeHTTP(resp, req)
+
+		if body := resp.Body.String(); !strings.Contains(body, `"response_type":"in_channel"`) {
+			t.Fatalf("request not allowed when store can't hold rate limits: %s", body)
+		}
+	}
+}
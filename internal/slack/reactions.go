@@ -0,0 +1,159 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+// reactionsCallbackID is the callback ID of the "Pick a winner from reactions"
+// message shortcut, which must match the shortcut's configuration in Slack, and
+// of the form that the shortcut opens.
+const reactionsCallbackID = "pick_from_reactions"
+
+const (
+	emojiBlockID  = "emoji"
+	emojiActionID = "emoji"
+)
+
+// reactionPickerMetadata is the private metadata of the form opened by the
+// "Pick a winner from reactions" shortcut, which identifies the message that
+// the shortcut was used on.
+type reactionPickerMetadata struct {
+	ChannelID string `json:"channel_id"`
+	MessageTS string `json:"message_ts"`
+}
+
+// openReactionPicker opens a form for the "Pick a winner from reactions"
+// shortcut, which lets the user choose the emoji to count before picking.
+func (a App) openReactionPicker(ctx context.Context, payload interactionPayload) error {
+	if a.Client == nil {
+		return errors.New("no Web API client configured to pick from reactions")
+	}
+
+	metadata, err := json.Marshal(reactionPickerMetadata{
+		ChannelID: payload.Channel.ID,
+		MessageTS: payload.Message.TS,
+	})
+	if err != nil {
+		return err
+	}
+
+	view := modalView{
+		Type:            "modal",
+		CallbackID:      reactionsCallbackID,
+		Title:           text{Type: "plain_text", Text: "Pick a Winner"},
+		Submit:          text{Type: "plain_text", Text: "Pick"},
+		Close:           text{Type: "plain_text", Text: "Cancel"},
+		PrivateMetadata: string(metadata),
+		Blocks: []block{{
+			Type:     "input",
+			BlockID:  emojiBlockID,
+			Label:    &text{Type: "plain_text", Text: "Only count these reactions"},
+			Element:  plainTextInput{Type: "plain_text_input", ActionID: emojiActionID},
+			Hint:     &text{Type: "plain_text", Text: "List emoji like :tada: :+1:, or leave this empty to count every reaction."},
+			Optional: true,
+		}},
+	}
+	return a.Client.OpenView(withInstallation(ctx, payload.workspace()), payload.TriggerID, view)
+}
+
+// submitReactionPicker picks a winner from the users who reacted to a message,
+// and returns the response to the submission of the form from
+// openReactionPicker. If it can't pick a winner, the response shows the
+// problem on the form and keeps it open. Otherwise, the form closes, and the
+// result is posted in a thread on the message.
+func (a App) submitReactionPicker(ctx context.Context, payload interactionPayload) (any, error) {
+	var metadata reactionPickerMetadata
+	if err := json.Unmarshal([]byte(payload.View.PrivateMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("decoding reaction picker metadata: %w", err)
+	}
+	if a.Client == nil {
+		return nil, errors.New("no Web API client configured to pick from reactions")
+	}
+
+	var emoji []string
+	for _, field := range strings.Fields(payload.View.State.Values[emojiBlockID][emojiActionID].Value) {
+		name, _, _ := strings.Cut(strings.Trim(field, ":"), "::")
+		if name != "" {
+			emoji = append(emoji, name)
+		}
+	}
+	pickerError := func(message string) viewErrors {
+		return viewErrors{ResponseAction: "errors", Errors: map[string]string{emojiBlockID: message}}
+	}
+
+	ws := payload.workspace()
+	reactions, err := a.Client.Reactions(withInstallation(ctx, ws), metadata.ChannelID, metadata.MessageTS)
+	if err != nil {
+		a.logErr(err, "Failed to get reactions")
+		return pickerError("Whoops, I had trouble getting the reactions to that message. Please try again later!"), nil
+	}
+
+	users := reactingUsers(reactions, emoji)
+	if len(users) < 2 {
+		if len(emoji) > 0 {
+			return pickerError("Whoops, I need at least two people who reacted with those emoji to pick a winner!"), nil
+		}
+		return pickerError("Whoops, I need at least two people who reacted to that message to pick a winner!"), nil
+	}
+
+	inv := invocation{
+		Command:   defaultCommand,
+		Workspace: ws,
+		ChannelID: metadata.ChannelID,
+		UserID:    payload.User.ID,
+		Args:      make([]string, len(users)),
+	}
+	for i, user := range users {
+		inv.Args[i] = mention(user)
+	}
+	result, err := a.runRandomizer(ctx, inv)
+	if err != nil {
+		a.logErr(err, "Failed to run randomizer")
+		return pickerError(webhook.HelpText(err)), nil
+	}
+
+	text := fmt.Sprintf(
+		"%s picked a winner from the %d people who reacted to this message. %s The first one wins!",
+		mention(inv.UserID), len(users), result.Message(),
+	)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), MaxDeferredDuration)
+	a.runInBackground(func() {
+		defer cancel()
+		msg := Message{Channel: metadata.ChannelID, ThreadTS: metadata.MessageTS, Text: text}
+		if err := a.Client.PostMessage(withInstallation(ctx, ws), msg); err != nil {
+			a.logErr(err, "Failed to post reaction winner")
+		}
+	})
+	return nil, nil
+}
+
+// reactingUsers returns the IDs of the users who reacted with any of the emoji
+// in the list, or with any emoji at all if the list is empty, in the order
+// that they first appear. Skin tones don't matter, so that "+1" counts
+// reactions with "+1::skin-tone-2" and the like.
+func reactingUsers(reactions []Reaction, emoji []string) []string {
+	var (
+		users []string
+		seen  = make(map[string]bool)
+	)
+	for _, reaction := range reactions {
+		name, _, _ := strings.Cut(reaction.Name, "::")
+		if len(emoji) > 0 && !slices.Contains(emoji, name) {
+			continue
+		}
+		for _, user := range reaction.Users {
+			if !seen[user] {
+				seen[user] = true
+				users = append(users, user)
+			}
+		}
+	}
+	return users
+}
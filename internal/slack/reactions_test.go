@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func sendTestInteraction(t *testing.T, app App, payload map[string]any) string {
+	t.Helper()
+	payload["token"] = "right"
+	payloadJSON, _ := json.Marshal(payload)
+	form := url.Values{"payload": {string(payloadJSON)}}
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.InteractionHandler().ServeHTTP(resp, req)
+	if resp.Result().StatusCode != http.StatusOK {
+		t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+	}
+	return strings.TrimSpace(resp.Body.String())
+}
+
+func TestReactionShortcut(t *testing.T) {
+	fake := &fakeSlack{Token: "xoxb-test"}
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return rndtest.Store{} },
+		Client:        fake.start(t),
+	}
+
+	sendTestInteraction(t, app, map[string]any{
+		"type":        "message_action",
+		"callback_id": reactionsCallbackID,
+		"trigger_id":  "trigger",
+		"user":        map[string]string{"id": "U1"},
+		"channel":     map[string]string{"id": "C1"},
+		"message":     map[string]string{"ts": "1700000000.000100", "text": "Giveaway!"},
+	})
+
+	views := fake.Views()
+	if len(views) != 1 {
+		t.Fatalf("got %d views opened, want 1", len(views))
+	}
+	var view modalView
+	if err := json.Unmarshal([]byte(views[0]), &view); err != nil {
+		t.Fatalf("failed to decode view: %v", err)
+	}
+	var metadata reactionPickerMetadata
+	json.Unmarshal([]byte(view.PrivateMetadata), &metadata)
+	want := reactionPickerMetadata{ChannelID: "C1", MessageTS: "1700000000.000100"}
+	if view.CallbackID != reactionsCallbackID || metadata != want {
+		t.Errorf("unexpected view: %s", views[0])
+	}
+}
+
+func TestReactionPickerSubmission(t *testing.T) {
+	testCases := []struct {
+		description string
+		emoji       string
+		wantBody    string
+		wantUsers   []string
+	}{
+		{
+			description: "counting every reaction",
+			wantUsers:   []string{"U2", "U3", "U4"},
+		},
+		{
+			description: "counting some emoji",
+			emoji:       ":+1: tada",
+			wantUsers:   []string{"U2", "U3"},
+		},
+		{
+			description: "with too few reactions",
+			emoji:       ":eyes:",
+			wantBody:    `{"response_action":"errors","errors":{"emoji":"Whoops, I need at least two people who reacted with those emoji to pick a winner!"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			fake := &fakeSlack{
+				Token: "xoxb-test",
+				Reactions: map[string][]Reaction{
+					"C1/1700000000.000100": {
+						{Name: "tada", Users: []string{"U2"}},
+						{Name: "+1::skin-tone-2", Users: []string{"U3", "U2"}},
+						{Name: "eyes", Users: []string{"U4"}},
+					},
+				},
+			}
+			app := App{
+				TokenProvider:   StaticToken("right"),
+				StoreFactory:    func(_ string) randomizer.Store { return rndtest.Store{} },
+				Client:          fake.start(t),
+				RunInBackground: func(task func()) { task() },
+			}
+
+			metadata, _ := json.Marshal(reactionPickerMetadata{ChannelID: "C1", MessageTS: "1700000000.000100"})
+			body := sendTestInteraction(t, app, map[string]any{
+				"type": "view_submission",
+				"user": map[string]string{"id": "U1"},
+				"view": map[string]any{
+					"callback_id":      reactionsCallbackID,
+					"private_metadata": string(metadata),
+					"state": map[string]any{
+						"values": map[string]any{
+							emojiBlockID: map[string]any{
+								emojiActionID: map[string]string{"type": "plain_text_input", "value": tc.emoji},
+							},
+						},
+					},
+				},
+			})
+			if body != tc.wantBody {
+				t.Errorf("got body %q, want %q", body, tc.wantBody)
+			}
+
+			messages := fake.Messages()
+			if tc.wantUsers == nil {
+				if len(messages) > 0 {
+					t.Errorf("unexpected messages posted: %v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 {
+				t.Fatalf("got %d messages posted, want 1", len(messages))
+			}
+			msg := messages[0]
+			if msg.Channel != "C1" || msg.ThreadTS != "1700000000.000100" || !strings.HasPrefix(msg.Text, "<@U1> picked a winner") {
+				t.Errorf("unexpected message: %+v", msg)
+			}
+			for _, user := range []string{"U2", "U3", "U4"} {
+				if got, want := strings.Contains(msg.Text, mention(user)), slices.Contains(tc.wantUsers, user); got != want {
+					t.Errorf("message includes %s: %v, want %v\n%s", user, got, want, msg.Text)
+				}
+			}
+		})
+	}
+}
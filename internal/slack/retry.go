@@ -0,0 +1,130 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRetryTTL is the default time that a RetryCache remembers a response,
+// covering the retries that Slack makes over the few minutes after a request
+// fails.
+const DefaultRetryTTL = 10 * time.Minute
+
+// RetryCache remembers the responses to recent requests from Slack, so that App
+// can answer a retried request with the original response instead of running
+// the randomizer again. Without it, a retried "/delete" would report that it
+// can't find the group that the original request deleted.
+//
+// Slack marks the requests that it retries with the X-Slack-Retry-Num header,
+// but that alone doesn't say whether the original request ran, or whether it's
+// still running. RetryCache instead recognizes each request by the trigger ID
+// (or, for events, the event ID) that Slack gives to it and to all of its
+// retries, including redeliveries over Socket Mode. If a retry arrives while
+// the original is still running, it waits for the original's response.
+//
+// A RetryCache only knows about the requests that its own process served.
+type RetryCache struct {
+	// TTL sets how long the cache remembers each response. If zero, the cache
+	// uses DefaultRetryTTL.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*retryEntry
+	now     func() time.Time // Overridden in tests for predictable behavior
+}
+
+// retryEntry holds the response to a request, which is ready once done is
+// closed.
+type retryEntry struct {
+	done    chan struct{}
+	value   any
+	err     error
+	expires time.Time
+}
+
+// do returns the value of fn for the first request with the given key, and
+// returns the same value for every later request with the key until it
+// expires. If fn returns an error, do returns it to any requests that were
+// waiting, but forgets it so that later retries run fn again.
+//
+// A nil RetryCache, or an empty key, runs fn for every request.
+func (c *RetryCache) do(ctx context.Context, key string, fn func() (any, error)) (value any, retried bool, err error) {
+	if c == nil || key == "" {
+		value, err = fn()
+		return value, false, err
+	}
+
+	c.mu.Lock()
+	c.pruneLocked()
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.value, true, entry.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	entry := &retryEntry{done: make(chan struct{})}
+	if c.entries == nil {
+		c.entries = make(map[string]*retryEntry)
+	}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	// A panic in fn must not leave retries waiting forever.
+	completed := false
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !completed {
+			entry.err = errors.New("original request panicked")
+		}
+		if entry.err == nil {
+			entry.expires = c.timeNow().Add(c.ttl())
+		} else {
+			delete(c.entries, key)
+		}
+		close(entry.done)
+	}()
+
+	entry.value, entry.err = fn()
+	completed = true
+	return entry.value, false, entry.err
+}
+
+// retryKey returns the key for a kind of request with a trigger or event ID,
+// or an empty key if there's no ID to recognize the request by.
+func retryKey(kind, id string) string {
+	if id == "" {
+		return ""
+	}
+	return kind + ":" + id
+}
+
+// pruneLocked forgets the responses that have expired. The caller must hold
+// c.mu.
+func (c *RetryCache) pruneLocked() {
+	now := c.timeNow()
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *RetryCache) ttl() time.Duration {
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return DefaultRetryTTL
+}
+
+func (c *RetryCache) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
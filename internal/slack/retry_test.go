@@ -0,0 +1,178 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestRetriedCommand(t *testing.T) {
+	store := rndtest.Store{"test": {"one", "two"}}
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return store },
+		RetryCache:    &RetryCache{},
+	}
+
+	sendCommand := func(triggerID, retryNum string) string {
+		params := makeTestParams("/delete test")
+		params.Set("trigger_id", triggerID)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if retryNum != "" {
+			req.Header.Set("X-Slack-Retry-Num", retryNum)
+		}
+		app.ServeHTTP(resp, req)
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+		}
+		return resp.Body.String()
+	}
+
+	original := sendCommand("trigger1", "")
+	if !strings.Contains(original, `The \"test\" group was deleted`) {
+		t.Fatalf("unexpected original response: %s", original)
+	}
+	if _, ok := store["test"]; ok {
+		t.Fatal("group not deleted by original request")
+	}
+
+	// A retry gets the original response, even though the group is gone.
+	if retry := sendCommand("trigger1", "1"); retry != original {
+		t.Errorf("retry got a different response\ngot:  %s\nwant: %s", retry, original)
+	}
+
+	// A new command with the same text runs again.
+	if again := sendCommand("trigger2", ""); !strings.Contains(again, "can't find") {
+		t.Errorf("unexpected response to new command: %s", again)
+	}
+}
+
+func TestRetriedInteraction(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		responses []string
+	)
+	responseSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		responses = append(responses, string(body))
+	}))
+	defer responseSrv.Close()
+
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return rndtest.Store{} },
+		HTTPClient:    responseSrv.Client(),
+		RetryCache:    &RetryCache{},
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"type":         "block_actions",
+		"token":        "right",
+		"trigger_id":   "trigger1",
+		"response_url": responseSrv.URL,
+		"user":         map[string]string{"id": "U1"},
+		"channel":      map[string]string{"id": "C1"},
+		"actions":      []map[string]string{{"action_id": rerollActionID, "value": `{"command":"/randomize","args":["one","two"]}`}},
+	})
+	form := url.Values{"payload": {string(payload)}}
+
+	for range 3 {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		app.InteractionHandler().ServeHTTP(resp, req)
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("invalid status: got %v, want %v", resp.Result().StatusCode, http.StatusOK)
+		}
+	}
+
+	if len(responses) != 1 {
+		t.Errorf("got %d rerolls posted, want 1", len(responses))
+	}
+}
+
+func TestRetryCache(t *testing.T) {
+	t.Run("waiting for the original", func(t *testing.T) {
+		var (
+			c       RetryCache
+			calls   int
+			started = make(chan struct{})
+			finish  = make(chan struct{})
+		)
+		fn := func() (any, error) {
+			calls++
+			close(started)
+			<-finish
+			return "original", nil
+		}
+
+		go c.do(context.Background(), "key", fn)
+		<-started
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(finish)
+		}()
+
+		value, retried, err := c.do(context.Background(), "key", fn)
+		if value != "original" || !retried || err != nil {
+			t.Errorf("got (%v, %v, %v), want (original, true, nil)", value, retried, err)
+		}
+		if calls != 1 {
+			t.Errorf("function called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("forgetting errors", func(t *testing.T) {
+		var c RetryCache
+		var results []any
+		for _, fn := range []func() (any, error){
+			func() (any, error) { return nil, errors.New("failed") },
+			func() (any, error) { return "second", nil },
+			func() (any, error) { return "third", nil },
+		} {
+			value, _, _ := c.do(context.Background(), "key", fn)
+			results = append(results, value)
+		}
+		if want := []any{nil, "second", "second"}; !reflect.DeepEqual(results, want) {
+			t.Errorf("got %v, want %v", results, want)
+		}
+	})
+
+	t.Run("expiring responses", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		c := RetryCache{TTL: time.Minute, now: func() time.Time { return now }}
+		c.do(context.Background(), "key", func() (any, error) { return "first", nil })
+
+		now = now.Add(2 * time.Minute)
+		value, retried, _ := c.do(context.Background(), "key", func() (any, error) { return "second", nil })
+		if value != "second" || retried {
+			t.Errorf("got (%v, %v), want (second, false)", value, retried)
+		}
+	})
+
+	t.Run("without a trigger ID", func(t *testing.T) {
+		var c RetryCache
+		for i := range 2 {
+			value, retried, _ := c.do(context.Background(), retryKey("command", ""), func() (any, error) { return i, nil })
+			if value != i || retried {
+				t.Errorf("got (%v, %v), want (%v, false)", value, retried, i)
+			}
+		}
+	})
+}
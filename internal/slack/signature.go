@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureMaxAge is the maximum difference between the current time and the
+// timestamp of a signed request. App rejects signed requests outside of this
+// window to protect against replay attacks.
+const SignatureMaxAge = 5 * time.Minute
+
+// SigningSecretProvider provides the signing secret that Slack uses to sign
+// its requests. This can be obtained from the "Basic Information" page of the
+// Slack app configuration.
+type SigningSecretProvider func(ctx context.Context) (string, error)
+
+// SigningSecretProviderFromEnv returns a SigningSecretProvider based on
+// available environment variables.
+//
+// If SLACK_SIGNING_SECRET is set, it returns a static signing secret provider.
+//
+// If SLACK_SIGNING_SECRET_SSM_NAME is set, it returns an AWS SSM signing
+// secret provider, with the TTL optionally set by SLACK_TOKEN_SSM_TTL.
+//
+// Otherwise, it returns a nil SigningSecretProvider and a nil error, as the
+// App can verify requests with a legacy verification token instead. See
+// [TokenProviderFromEnv].
+func SigningSecretProviderFromEnv() (SigningSecretProvider, error) {
+	if secret, ok := os.LookupEnv("SLACK_SIGNING_SECRET"); ok {
+		return StaticSigningSecret(secret), nil
+	}
+
+	if ssmName, ok := os.LookupEnv("SLACK_SIGNING_SECRET_SSM_NAME"); ok {
+		ttl, err := ssmTTLFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return AWSParameterSigningSecret(ssmName, ttl), nil
+	}
+
+	return nil, nil
+}
+
+// StaticSigningSecret uses secret as the signing secret.
+func StaticSigningSecret(secret string) SigningSecretProvider {
+	return func(_ context.Context) (string, error) {
+		return secret, nil
+	}
+}
+
+// AWSParameterSigningSecret retrieves the signing secret from the AWS SSM
+// Parameter Store, decrypting it if necessary, and caches the retrieved value
+// for the provided TTL.
+func AWSParameterSigningSecret(name string, ttl time.Duration) SigningSecretProvider {
+	return SigningSecretProvider(ssmParameter(name, ttl))
+}
+
+// VerificationMode controls how App combines multiple methods of verifying
+// that requests came from Slack.
+type VerificationMode int
+
+const (
+	// VerifyAll requires requests to pass every configured verification method.
+	VerifyAll VerificationMode = iota
+	// VerifyAny accepts requests that pass any configured verification method.
+	// This is useful while migrating from a verification token to a signing
+	// secret.
+	VerifyAny
+)
+
+// VerificationModeFromEnv returns a VerificationMode based on the value of
+// SLACK_VERIFICATION_MODE, which may be "all" (the default) or "any".
+func VerificationModeFromEnv() (VerificationMode, error) {
+	switch env := os.Getenv("SLACK_VERIFICATION_MODE"); env {
+	case "", "all":
+		return VerifyAll, nil
+	case "any":
+		return VerifyAny, nil
+	default:
+		return 0, fmt.Errorf("SLACK_VERIFICATION_MODE must be all or any, not %q", env)
+	}
+}
+
+// isSignatureValid checks the X-Slack-Signature header of a request, which
+// Slack computes over the X-Slack-Request-Timestamp header and the raw request
+// body.
+func (a App) isSignatureValid(ctx context.Context, header http.Header, body []byte) (ok bool, _ error) {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > SignatureMaxAge || age < -SignatureMaxAge {
+		return false, nil
+	}
+
+	gotSignature, found := strings.CutPrefix(header.Get("X-Slack-Signature"), "v0=")
+	if !found {
+		return false, nil
+	}
+	gotMAC, err := hex.DecodeString(gotSignature)
+	if err != nil {
+		return false, nil
+	}
+
+	secret, err := a.SigningSecretProvider(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	subtle.WithDataIndependentTiming(func() {
+		ok = hmac.Equal(gotMAC, computeSignature(secret, timestamp, body))
+	})
+	return
+}
+
+func computeSignature(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func TestRequestVerification(t *testing.T) {
+	const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+	var (
+		now   = time.Now()
+		stale = now.Add(-2 * SignatureMaxAge)
+	)
+
+	testCases := []struct {
+		description string
+		token       bool
+		secret      bool
+		mode        VerificationMode
+		reqToken    string
+		signWith    string
+		signedAt    time.Time
+		wantStatus  int
+	}{
+		{
+			description: "valid signature",
+			secret:      true,
+			signWith:    secret,
+			signedAt:    now,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "invalid signature",
+			secret:      true,
+			signWith:    "wrong",
+			signedAt:    now,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "missing signature",
+			secret:      true,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "stale signature",
+			secret:      true,
+			signWith:    secret,
+			signedAt:    stale,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "both valid requiring all",
+			token:       true,
+			secret:      true,
+			reqToken:    "right",
+			signWith:    secret,
+			signedAt:    now,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "only signature valid requiring all",
+			token:       true,
+			secret:      true,
+			reqToken:    "wrong",
+			signWith:    secret,
+			signedAt:    now,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "only signature valid requiring any",
+			token:       true,
+			secret:      true,
+			mode:        VerifyAny,
+			reqToken:    "wrong",
+			signWith:    secret,
+			signedAt:    now,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "only token valid requiring any",
+			token:       true,
+			secret:      true,
+			mode:        VerifyAny,
+			reqToken:    "right",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			description: "neither valid requiring any",
+			token:       true,
+			secret:      true,
+			mode:        VerifyAny,
+			reqToken:    "wrong",
+			signWith:    "wrong",
+			signedAt:    now,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			description: "no verification configured",
+			reqToken:    "right",
+			wantStatus:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			app := App{
+				VerificationMode: tc.mode,
+				StoreFactory:     func(_ string) randomizer.Store { return make(rndtest.Store) },
+			}
+			if tc.token {
+				app.TokenProvider = StaticToken("right")
+			}
+			if tc.secret {
+				app.SigningSecretProvider = StaticSigningSecret(secret)
+			}
+
+			params := makeTestParams("one two three")
+			params.Set("token", tc.reqToken)
+			body := params.Encode()
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.signWith != "" {
+				timestamp := strconv.FormatInt(tc.signedAt.Unix(), 10)
+				signature := computeSignature(tc.signWith, timestamp, []byte(body))
+				req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+				req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(signature))
+			}
+
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, req)
+
+			if got := resp.Result().StatusCode; got != tc.wantStatus {
+				t.Errorf("got status %v, want %v", got, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSignatureExample(t *testing.T) {
+	// This example comes from Slack's documentation on verifying requests.
+	const (
+		secret    = "8f742231b10e8888abcd99yyyzzz85a5"
+		timestamp = "1531420618"
+		body      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+		want      = "a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+	)
+	got := hex.EncodeToString(computeSignature(secret, timestamp, []byte(body)))
+	if got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+}
@@ -2,77 +2,242 @@
 package slack
 
 import (
+	"bytes"
 	"context"
 	"crypto/subtle"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/webhook"
 )
 
 // App serves the randomizer through the Slack slash command API.
 //
-// App supports legacy static verification tokens to confirm the legitimacy of
-// requests from Slack; it does not support the newer signed secrets strategy.
+// App confirms the legitimacy of requests from Slack by checking their
+// signatures with the app's signing secret, by checking the legacy static
+// verification token in their parameters, or both.
 //
 // App supports only HTTP POST requests; it does not support the GET requests
 // allowed by the deprecated legacy slash command integration.
 type App struct {
-	// TokenProvider provides the expected value of the slash command verification
-	// token generated by Slack. This can be obtained from the slash command
-	// configuration.
+	// TokenProvider, if non-nil, provides the expected value of the slash
+	// command verification token generated by Slack. This can be obtained from
+	// the slash command configuration.
 	TokenProvider TokenProvider
+	// SigningSecretProvider, if non-nil, provides the secret that Slack uses to
+	// sign requests.
+	SigningSecretProvider SigningSecretProvider
+	// VerificationMode controls whether requests must pass verification with
+	// all of the configured providers, or only one of them. At least one of
+	// TokenProvider or SigningSecretProvider must be non-nil.
+	VerificationMode VerificationMode
 	// StoreFactory provides a Store for the Slack channel in which the request
-	// was made.
+	// was made, or for the installation of the randomizer in a workspace (see
+	// OAuth).
 	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Client, if non-nil, provides access to the Slack Web API. This permits
+	// users to randomize the members of Slack user groups, by mentioning them
+	// in their options or in saved groups, and the members of the current
+	// channel, with the "/members" keyword.
+	Client Client
+	// OAuth, if non-nil, permits installing the randomizer into multiple Slack
+	// workspaces. Each workspace's channels get their own partitions, named by
+	// the IDs of the workspace (and its Enterprise Grid organization, if any)
+	// and the channel, separated by colons. Without OAuth, App names partitions
+	// by channel IDs alone, which is only safe for a single workspace.
+	OAuth *OAuthConfig
+	// HTTPClient, if non-nil, overrides http.DefaultClient for requests to the
+	// response URLs that Slack provides to update messages, and for OAuth.
+	HTTPClient *http.Client
+	// DeferAfter, if positive, limits the time that App waits for the
+	// randomizer to finish a slash command. If the randomizer takes longer, as
+	// it might with a slow store, App acknowledges the command right away and
+	// posts the result to the command's response URL once it's ready.
+	DeferAfter time.Duration
+	// RunInBackground, if non-nil, runs the work that continues after App
+	// acknowledges a slash command. If nil, App runs this work in a new
+	// goroutine. Environments that suspend or stop the process after serving a
+	// request must keep it running until the work finishes.
+	RunInBackground func(task func())
+	// RateLimiter, if non-nil, limits the rate at which each user can run the
+	// randomizer, and the rate at which it runs in each channel. Requests over
+	// the limit get a friendly error.
+	RateLimiter *RateLimiter
+	// RetryCache, if non-nil, remembers the responses to recent slash commands
+	// and interactions, and the events that App has handled, so that App
+	// answers any retries of them without running the randomizer again.
+	RetryCache *RetryCache
+	// HomeCache, if non-nil, remembers the partitions of each installation for
+	// the App Home tab, rather than listing them every time a user opens it.
+	HomeCache *HomeCache
 	// Logger, if non-nil, logs errors encountered during request handling.
 	Logger *slog.Logger
 }
 
 // ServeHTTP serves POST requests from Slack.
 func (a App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, _, ok := a.readRequest(w, r, func(params url.Values, _ []byte) string { return params.Get("token") })
+	if !ok {
+		return
+	}
+
+	if params.Get("ssl_check") == "1" {
+		return
+	}
+
+	resp, ok := a.handleCommand(r.Context(), params)
+	if !ok {
+		acknowledge(w)
+		return
+	}
+	webhook.WriteJSON(w, a.Logger, resp)
+}
+
+// handleCommand runs the randomizer for a verified slash command, and returns
+// the response to show to the user. If the randomizer takes longer than the
+// DeferAfter budget, handleCommand returns false, and posts the response to
+// the command's response URL once it's ready. It also returns false after
+// opening the group editor in place of a response.
+//
+// If the command is a retry of one that App already handled, handleCommand
+// returns the original response.
+func (a App) handleCommand(ctx context.Context, params url.Values) (response, bool) {
+	type commandResponse struct {
+		resp response
+		ok   bool
+	}
+	triggerID := params.Get("trigger_id")
+	value, retried, err := a.RetryCache.do(ctx, retryKey("command", triggerID), func() (any, error) {
+		resp, ok := a.runCommand(ctx, params)
+		return commandResponse{resp, ok}, nil
+	})
+	if err != nil {
+		// The original request is still running, and will respond on its own.
+		return response{}, false
+	}
+	if retried {
+		a.logRetry(triggerID)
+	}
+	cr := value.(commandResponse)
+	return cr.resp, cr.ok
+}
+
+func (a App) runCommand(ctx context.Context, params url.Values) (response, bool) {
+	inv := invocation{
+		Command: params.Get("command"),
+		Workspace: workspace{
+			EnterpriseID:      params.Get("enterprise_id"),
+			TeamID:            params.Get("team_id"),
+			EnterpriseInstall: params.Get("is_enterprise_install") == "true",
+		},
+		ChannelID: params.Get("channel_id"),
+		UserID:    params.Get("user_id"),
+		Args:      strings.Fields(params.Get("text")),
+	}
+
+	var out outcome
+	if responseURL := params.Get("response_url"); a.DeferAfter > 0 && responseURL != "" {
+		var ok bool
+		out, ok = a.runDeferrable(ctx, inv, responseURL)
+		if !ok {
+			return response{}, false
+		}
+	} else {
+		out.result, out.err = a.runRandomizer(ctx, inv)
+	}
+
+	if out.err != nil {
+		a.logErr(out.err, "Failed to run randomizer")
+	} else if out.result.Type() == randomizer.EditingGroup &&
+		a.openEditor(ctx, inv, params.Get("trigger_id"), params.Get("response_url"), out.result) {
+		return response{}, false
+	}
+	return a.outcomeResponse(inv, out), true
+}
+
+// readRequest reads the form parameters and raw body of a POST request from
+// Slack, and verifies that the request legitimately came from Slack, using
+// getToken to find the legacy verification token in the request. If
+// readRequest returns false, it has already written an error response.
+func (a App) readRequest(w http.ResponseWriter, r *http.Request, getToken func(url.Values, []byte) string) (url.Values, []byte, bool) {
 	if r.Method != http.MethodPost {
 		w.Header().Add("Allow", http.MethodPost)
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		return
+		return nil, nil, false
+	}
+
+	// Signature verification needs the raw body, which ParseForm consumes.
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhook.MaxBodySize))
+	if err != nil {
+		a.logErr(err, "Failed to read request body")
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, nil, false
 	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
 
 	if err := r.ParseForm(); err != nil {
 		a.logErr(err, "Failed to read POST form")
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, nil, false
 	}
 
-	tokenIsValid, err := a.isTokenValid(r.Context(), r.PostForm)
+	requestIsValid, err := a.isRequestValid(r.Context(), r.Header, body, getToken(r.PostForm, body))
 	if err != nil {
-		a.logErr(err, "Failed to validate token")
+		a.logErr(err, "Failed to verify request")
 		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, nil, false
 	}
-	if !tokenIsValid {
+	if !requestIsValid {
 		w.WriteHeader(http.StatusForbidden)
-		return
+		return nil, nil, false
 	}
 
-	if r.PostForm.Get("ssl_check") == "1" {
-		return
+	return r.PostForm, body, true
+}
+
+// isRequestValid checks a request with every configured verification method,
+// and combines the results according to the VerificationMode.
+func (a App) isRequestValid(ctx context.Context, header http.Header, body []byte, token string) (bool, error) {
+	var results []bool
+
+	if a.TokenProvider != nil {
+		ok, err := a.isTokenValid(ctx, token)
+		if err != nil {
+			return false, fmt.Errorf("validating token: %w", err)
+		}
+		results = append(results, ok)
 	}
 
-	result, err := a.runRandomizer(r.Context(), r.PostForm)
-	if err != nil {
-		a.logErr(err, "Failed to run randomizer")
-		a.writeError(w, err)
-		return
+	if a.SigningSecretProvider != nil {
+		ok, err := a.isSignatureValid(ctx, header, body)
+		if err != nil {
+			return false, fmt.Errorf("validating signature: %w", err)
+		}
+		results = append(results, ok)
+	}
+
+	if len(results) == 0 {
+		return false, errors.New("no verification token or signing secret configured")
 	}
 
-	a.writeResult(w, result)
+	if a.VerificationMode == VerifyAny {
+		return slices.Contains(results, true), nil
+	}
+	return !slices.Contains(results, false), nil
 }
 
-func (a App) isTokenValid(ctx context.Context, params url.Values) (ok bool, _ error) {
-	gotToken := params.Get("token")
+func (a App) isTokenValid(ctx context.Context, gotToken string) (ok bool, _ error) {
 	wantToken, err := a.TokenProvider(ctx)
 	if err != nil {
 		return false, err
@@ -84,20 +249,78 @@ func (a App) isTokenValid(ctx context.Context, params url.Values) (ok bool, _ er
 	return
 }
 
-func (a App) runRandomizer(ctx context.Context, params url.Values) (randomizer.Result, error) {
-	var (
-		name      = params.Get("command")
-		channelID = params.Get("channel_id")
-		args      = strings.Fields(params.Get("text"))
-	)
+// invocation represents a single request to run the randomizer.
+type invocation struct {
+	Command   string
+	Workspace workspace
+	ChannelID string
+	UserID    string
+	Args      []string
+}
+
+// defaultCommand is the name that the randomizer uses for itself in requests
+// that don't come from a slash command, like those from the App Home tab or
+// from shortcuts.
+const defaultCommand = "/randomize"
 
-	app := randomizer.NewApp(name, a.StoreFactory(channelID))
-	return app.Main(ctx, args)
+func (a App) runRandomizer(ctx context.Context, inv invocation) (randomizer.Result, error) {
+	if err := a.checkRateLimit(ctx, inv); err != nil {
+		return randomizer.Result{}, err
+	}
+
+	ctx = withInstallation(ctx, inv.Workspace)
+	options := []randomizer.Option{randomizer.WithRules(a.Rules)}
+	if inv.UserID != "" {
+		options = append(options, randomizer.WithUser(mention(inv.UserID)))
+	}
+	if a.Client != nil {
+		options = append(options, randomizer.WithExpander(a.expandMembers(inv.ChannelID)))
+	}
+
+	app := randomizer.NewApp(inv.Command, a.StoreFactory(a.partition(inv.Workspace, inv.ChannelID)), options...)
+	return app.Main(ctx, inv.Args)
+}
+
+// subteamPattern matches a reference to a Slack user group in message text,
+// with or without the label that Slack adds to escaped references.
+var subteamPattern = regexp.MustCompile(`^<!subteam\^([A-Z0-9]+)(?:\|[^>]*)?>$`)
+
+// expandMembers returns an Expander that resolves Slack user group references
+// and the "/members" keyword into mentions of the users they represent.
+func (a App) expandMembers(channelID string) randomizer.Expander {
+	return func(ctx context.Context, arg string) ([]string, bool, error) {
+		var (
+			users []string
+			err   error
+		)
+		if arg == "/members" {
+			users, err = a.Client.ChannelMembers(ctx, channelID)
+		} else if match := subteamPattern.FindStringSubmatch(arg); match != nil {
+			users, err = a.Client.UserGroupMembers(ctx, match[1])
+		} else {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, true, err
+		}
+
+		mentions := make([]string, len(users))
+		for i, user := range users {
+			mentions[i] = mention(user)
+		}
+		return mentions, true, nil
+	}
+}
+
+func mention(userID string) string {
+	return "<@" + userID + ">"
 }
 
 type response struct {
-	Type responseType `json:"response_type"`
-	Text string       `json:"text"`
+	Type            responseType `json:"response_type,omitempty"`
+	Text            string       `json:"text"`
+	Blocks          []block      `json:"blocks,omitempty"`
+	ReplaceOriginal bool         `json:"replace_original,omitempty"`
 }
 
 type responseType string
@@ -107,36 +330,39 @@ const (
 	typeInChannel responseType = "in_channel"
 )
 
-func (a App) writeResult(w http.ResponseWriter, result randomizer.Result) {
+// resultResponse returns the response that shows result to the user who made
+// the original invocation.
+func (a App) resultResponse(inv invocation, result randomizer.Result) response {
 	rtype := typeEphemeral
-	switch result.Type() {
-	case randomizer.Selection, randomizer.SavedGroup, randomizer.DeletedGroup:
+	if result.Type().IsPublic() {
 		rtype = typeInChannel
 	}
 
-	a.writeResponse(w, response{
+	resp := response{
 		Text: result.Message(),
 		Type: rtype,
-	})
+	}
+	if result.Type() == randomizer.Selection {
+		resp.Blocks = selectionBlocks(inv, result.Message())
+	}
+	return resp
 }
 
-func (a App) writeError(w http.ResponseWriter, err error) {
-	a.writeResponse(w, response{
-		Text: err.(randomizer.Error).HelpText(),
+func errorResponse(err error) response {
+	return response{
+		Text: webhook.HelpText(err),
 		Type: typeEphemeral,
-	})
+	}
 }
 
-func (a App) writeResponse(w http.ResponseWriter, response response) {
-	w.Header().Add("Content-Type", "application/json")
-	err := json.NewEncoder(w).Encode(response)
-	if err != nil {
-		a.logErr(err, "Failed to write response")
+func (a App) logRetry(id string) {
+	if a.Logger != nil {
+		a.Logger.Info("Answered retried request with original response", "id", id)
 	}
 }
 
-func (a App) logErr(err error, msg string) {
+func (a App) logErr(err error, msg string, args ...any) {
 	if a.Logger != nil {
-		a.Logger.Error(msg, "err", err)
+		a.Logger.Error(msg, append([]any{"err", err}, args...)...)
 	}
 }
@@ -0,0 +1,283 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// DefaultMinReconnectDelay is the default time that SocketMode waits before
+	// reconnecting after a failed connection.
+	DefaultMinReconnectDelay = time.Second
+
+	// DefaultMaxReconnectDelay is the default limit on the time that SocketMode
+	// waits before reconnecting, after repeated failures.
+	DefaultMaxReconnectDelay = time.Minute
+)
+
+// AppTokenProviderFromEnv returns a TokenProvider for the app-level token that
+// SocketMode connects with, based on available environment variables.
+//
+// If SLACK_APP_TOKEN is set, it returns a static token provider.
+//
+// If SLACK_APP_TOKEN_SSM_NAME is set, it returns an AWS SSM token provider,
+// with the TTL optionally set by SLACK_TOKEN_SSM_TTL.
+//
+// Otherwise, it returns an error.
+func AppTokenProviderFromEnv() (TokenProvider, error) {
+	if token, ok := os.LookupEnv("SLACK_APP_TOKEN"); ok {
+		return StaticToken(token), nil
+	}
+
+	if ssmName, ok := os.LookupEnv("SLACK_APP_TOKEN_SSM_NAME"); ok {
+		ttl, err := ssmTTLFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return AWSParameter(ssmName, ttl), nil
+	}
+
+	return nil, errors.New("missing SLACK_APP_TOKEN or SLACK_APP_TOKEN_SSM_NAME in environment")
+}
+
+// SocketMode serves an App over a [Socket Mode] connection, for deployments
+// that can't accept HTTP requests from Slack. Instead of sending requests to
+// the App, Slack sends slash commands, interactions, and events over a
+// WebSocket connection that SocketMode opens.
+//
+// Slack authenticates the connection itself, so SocketMode ignores the
+// verification settings of its App.
+//
+// [Socket Mode]: https://api.slack.com/apis/socket-mode
+type SocketMode struct {
+	// App handles the requests that arrive over the connection.
+	App App
+	// AppTokenProvider provides an app-level token with the connections:write
+	// scope, which can be created on the "Basic Information" page of the Slack
+	// app configuration.
+	AppTokenProvider TokenProvider
+	// BaseURL, if non-empty, overrides DefaultBaseURL for the Web API call that
+	// opens each connection. It must end with a slash.
+	BaseURL string
+	// HTTPClient, if non-nil, overrides http.DefaultClient for the Web API call
+	// that opens each connection.
+	HTTPClient *http.Client
+	// MinReconnectDelay and MaxReconnectDelay, if positive, override
+	// DefaultMinReconnectDelay and DefaultMaxReconnectDelay. SocketMode doubles
+	// its delay after each failed connection, up to the maximum.
+	MinReconnectDelay time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// Run connects to Slack and serves requests until ctx is canceled, then waits
+// for the requests in progress to finish and returns nil. It reconnects
+// whenever Slack closes the connection, and backs off when connections fail.
+func (s SocketMode) Run(ctx context.Context) error {
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+
+	minDelay, maxDelay := s.MinReconnectDelay, s.MaxReconnectDelay
+	if minDelay <= 0 {
+		minDelay = DefaultMinReconnectDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxReconnectDelay
+	}
+
+	delay := minDelay
+	for {
+		connected, err := s.serveConnection(ctx, &inFlight)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			delay = minDelay
+		}
+		if err == nil {
+			// Slack asked us to reconnect, which we can do right away.
+			continue
+		}
+
+		s.App.logErr(err, "Socket Mode connection failed", "retryIn", delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxDelay)
+	}
+}
+
+// envelope represents a message that Slack sends over a Socket Mode
+// connection. It includes only the fields that the randomizer uses.
+type envelope struct {
+	Type        string          `json:"type"`
+	EnvelopeID  string          `json:"envelope_id"`
+	Payload     json.RawMessage `json:"payload"`
+	RetryReason string          `json:"retry_reason"`
+}
+
+// ack acknowledges an envelope, optionally with a response payload.
+type ack struct {
+	EnvelopeID string `json:"envelope_id"`
+	Payload    any    `json:"payload,omitempty"`
+}
+
+// serveConnection opens a new connection and serves requests until the
+// connection closes. It reports whether Slack accepted the connection, and
+// returns a nil error if Slack asked to disconnect.
+//
+// Requests are handled in the background, and tracked in inFlight. Leaving the
+// connection doesn't cancel them, and the connection stays open until they
+// finish, so that a request arriving just before a shutdown or reconnect is
+// still acknowledged with its result.
+func (s SocketMode) serveConnection(ctx context.Context, inFlight *sync.WaitGroup) (connected bool, err error) {
+	wsURL, err := s.openConnection(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	config, err := websocket.NewConfig(wsURL, "https://slack.com/")
+	if err != nil {
+		return false, fmt.Errorf("configuring WebSocket: %w", err)
+	}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("connecting to WebSocket: %w", err)
+	}
+
+	var pending sync.WaitGroup
+	defer func() {
+		inFlight.Add(1)
+		go func() {
+			defer inFlight.Done()
+			pending.Wait()
+			conn.Close()
+		}()
+	}()
+
+	// A read deadline interrupts a blocked read while leaving the connection
+	// open for acknowledgements.
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	var (
+		sendMu sync.Mutex
+		send   = func(a ack) error {
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			return websocket.JSON.Send(conn, a)
+		}
+	)
+
+	for {
+		var env envelope
+		if err := websocket.JSON.Receive(conn, &env); err != nil {
+			return connected, fmt.Errorf("reading from WebSocket: %w", err)
+		}
+
+		switch env.Type {
+		case "hello":
+			connected = true
+			if s.App.Logger != nil {
+				s.App.Logger.Info("Connected to Slack over Socket Mode")
+			}
+		case "disconnect":
+			return connected, nil
+		default:
+			if env.EnvelopeID == "" {
+				continue
+			}
+			pending.Add(1)
+			go func() {
+				defer pending.Done()
+				if err := s.dispatch(context.WithoutCancel(ctx), env, send); err != nil {
+					s.App.logErr(err, "Failed to handle Socket Mode request", "type", env.Type)
+				}
+			}()
+		}
+	}
+}
+
+// openConnection returns the URL for a new WebSocket connection from the
+// apps.connections.open method.
+func (s SocketMode) openConnection(ctx context.Context) (string, error) {
+	var response struct {
+		apiResponse
+		URL string `json:"url"`
+	}
+	client := WebClient{TokenProvider: s.AppTokenProvider, BaseURL: s.BaseURL, HTTPClient: s.HTTPClient}
+	if err := client.call(ctx, "apps.connections.open", nil, &response); err != nil {
+		return "", err
+	}
+	return response.URL, nil
+}
+
+// dispatch handles the request in an envelope with the same logic as the
+// App's HTTP handlers, and acknowledges it. Slack retries requests that
+// SocketMode doesn't acknowledge.
+func (s SocketMode) dispatch(ctx context.Context, env envelope, send func(ack) error) error {
+	switch env.Type {
+	case "slash_commands":
+		var fields map[string]any
+		if err := json.Unmarshal(env.Payload, &fields); err != nil {
+			return fmt.Errorf("decoding slash command: %w", err)
+		}
+		params := make(url.Values, len(fields))
+		for key, value := range fields {
+			params.Set(key, fmt.Sprint(value))
+		}
+
+		resp, ok := s.App.handleCommand(ctx, params)
+		if !ok {
+			return send(ack{EnvelopeID: env.EnvelopeID})
+		}
+		return send(ack{EnvelopeID: env.EnvelopeID, Payload: resp})
+
+	case "interactive":
+		var payload interactionPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("decoding interaction: %w", err)
+		}
+		// View submissions take their response in the acknowledgement, while
+		// other interactions should be acknowledged before their results.
+		if payload.Type == "view_submission" {
+			body, err := s.App.handleInteraction(ctx, payload)
+			if err != nil {
+				return err
+			}
+			return send(ack{EnvelopeID: env.EnvelopeID, Payload: body})
+		}
+		if err := send(ack{EnvelopeID: env.EnvelopeID}); err != nil {
+			return err
+		}
+		_, err := s.App.handleInteraction(ctx, payload)
+		return err
+
+	case "events_api":
+		var payload eventPayload
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return fmt.Errorf("decoding event: %w", err)
+		}
+		// As with HTTP, a retry after a timeout most likely means that the
+		// original delivery made it through.
+		if env.RetryReason != "timeout" {
+			if err := s.App.handleEvent(ctx, payload); err != nil {
+				return err
+			}
+		}
+		return send(ack{EnvelopeID: env.EnvelopeID})
+
+	default:
+		return send(ack{EnvelopeID: env.EnvelopeID})
+	}
+}
@@ -0,0 +1,248 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+// fakeSocketMode is a stand-in for Slack's Socket Mode endpoints. It fails the
+// first attempt to open a connection, and serves each following connection
+// with serve.
+type fakeSocketMode struct {
+	serve func(conn *websocket.Conn, n int)
+
+	mu    sync.Mutex
+	opens int
+	conns int
+}
+
+func (f *fakeSocketMode) start(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/api/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.opens++
+		opens := f.opens
+		f.mu.Unlock()
+
+		switch {
+		case r.Header.Get("Authorization") != "Bearer xapp-test":
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "invalid_auth"})
+		case opens == 1:
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "ratelimited"})
+		default:
+			wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/socket"
+			writeFakeResponse(w, map[string]any{"ok": true, "url": wsURL})
+		}
+	})
+
+	mux.Handle("/socket", websocket.Handler(func(conn *websocket.Conn) {
+		f.mu.Lock()
+		f.conns++
+		n := f.conns
+		f.mu.Unlock()
+		f.serve(conn, n)
+	}))
+
+	return srv
+}
+
+func TestSocketMode(t *testing.T) {
+	acks := make(chan ack, 1)
+	reconnected := make(chan struct{})
+
+	fake := &fakeSocketMode{
+		serve: func(conn *websocket.Conn, n int) {
+			websocket.JSON.Send(conn, map[string]any{"type": "hello"})
+			if n > 1 {
+				close(reconnected)
+				var discard json.RawMessage
+				for websocket.JSON.Receive(conn, &discard) == nil {
+				}
+				return
+			}
+
+			websocket.JSON.Send(conn, map[string]any{
+				"type":        "slash_commands",
+				"envelope_id": "env1",
+				"payload": map[string]any{
+					"token":      "ignored",
+					"channel_id": "C12345678",
+					"command":    "/randomize",
+					"text":       "test",
+				},
+			})
+
+			var got struct {
+				EnvelopeID string   `json:"envelope_id"`
+				Payload    response `json:"payload"`
+			}
+			if err := websocket.JSON.Receive(conn, &got); err != nil {
+				t.Errorf("failed to receive ack: %v", err)
+				return
+			}
+			acks <- ack{EnvelopeID: got.EnvelopeID, Payload: got.Payload}
+
+			websocket.JSON.Send(conn, map[string]any{"type": "disconnect", "reason": "refresh_requested"})
+		},
+	}
+	srv := fake.start(t)
+
+	socket := SocketMode{
+		App: App{
+			StoreFactory: func(_ string) randomizer.Store {
+				return rndtest.Store{"test": {"one", "two"}}
+			},
+		},
+		AppTokenProvider:  StaticToken("xapp-test"),
+		BaseURL:           srv.URL + "/api/",
+		HTTPClient:        srv.Client(),
+		MinReconnectDelay: time.Millisecond,
+		MaxReconnectDelay: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- socket.Run(ctx) }()
+
+	select {
+	case got := <-acks:
+		if got.EnvelopeID != "env1" {
+			t.Errorf("got ack for envelope %q, want %q", got.EnvelopeID, "env1")
+		}
+		resp := got.Payload.(response)
+		if resp.Type != typeInChannel || !strings.Contains(resp.Text, "I randomized and got") {
+			t.Errorf("unexpected ack payload: %+v", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for slash command ack")
+	}
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect after disconnect")
+	}
+
+	cancel()
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Run() returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after cancellation")
+	}
+}
+
+// blockingStore is a store whose Get blocks until release is closed, and then
+// reports the state of its context.
+type blockingStore struct {
+	rndtest.Store
+	started chan struct{}
+	release chan struct{}
+	ctxErr  chan error
+}
+
+func (s blockingStore) Get(ctx context.Context, name string) ([]string, error) {
+	close(s.started)
+	<-s.release
+	s.ctxErr <- ctx.Err()
+	return s.Store.Get(ctx, name)
+}
+
+func TestSocketModeShutdown(t *testing.T) {
+	store := blockingStore{
+		Store:   rndtest.Store{"test": {"one", "two"}},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+		ctxErr:  make(chan error, 1),
+	}
+	acks := make(chan string, 1)
+
+	fake := &fakeSocketMode{
+		serve: func(conn *websocket.Conn, _ int) {
+			websocket.JSON.Send(conn, map[string]any{"type": "hello"})
+			websocket.JSON.Send(conn, map[string]any{
+				"type":        "slash_commands",
+				"envelope_id": "env1",
+				"payload": map[string]any{
+					"channel_id": "C12345678",
+					"command":    "/randomize",
+					"text":       "test",
+				},
+			})
+			var got struct {
+				EnvelopeID string `json:"envelope_id"`
+			}
+			if err := websocket.JSON.Receive(conn, &got); err != nil {
+				t.Errorf("failed to receive ack: %v", err)
+				return
+			}
+			acks <- got.EnvelopeID
+		},
+	}
+	srv := fake.start(t)
+
+	socket := SocketMode{
+		App:               App{StoreFactory: func(_ string) randomizer.Store { return store }},
+		AppTokenProvider:  StaticToken("xapp-test"),
+		BaseURL:           srv.URL + "/api/",
+		HTTPClient:        srv.Client(),
+		MinReconnectDelay: time.Millisecond,
+		MaxReconnectDelay: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- socket.Run(ctx) }()
+
+	select {
+	case <-store.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for slash command")
+	}
+
+	cancel()
+	select {
+	case err := <-runErr:
+		t.Fatalf("Run() returned %v with a request in progress", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(store.release)
+	if err := <-store.ctxErr; err != nil {
+		t.Errorf("request context was canceled: %v", err)
+	}
+	select {
+	case got := <-acks:
+		if got != "env1" {
+			t.Errorf("got ack for envelope %q, want %q", got, "env1")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for slash command ack")
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Run() returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after the request finished")
+	}
+}
@@ -2,7 +2,6 @@ package slack
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -15,8 +14,9 @@ import (
 
 const DefaultAWSParameterTTL = 2 * time.Minute
 
-// TokenProvider provides the expected value of the slash command verification
-// token that Slack includes in its requests.
+// TokenProvider provides the value of a secret token, like the slash command
+// verification token that Slack includes in its requests, or the bot token
+// that authenticates requests to the Slack Web API.
 type TokenProvider func(ctx context.Context) (string, error)
 
 // TokenProviderFromEnv returns a TokenProvider based on available environment
@@ -27,7 +27,9 @@ type TokenProvider func(ctx context.Context) (string, error)
 // If SLACK_TOKEN_SSM_NAME is set, it returns an AWS SSM token provider,
 // with the TTL optionally set by SLACK_TOKEN_SSM_TTL.
 //
-// Otherwise, it returns an error.
+// Otherwise, it returns a nil TokenProvider and a nil error, as the App can
+// verify requests with a signing secret instead. See
+// [SigningSecretProviderFromEnv].
 func TokenProviderFromEnv() (TokenProvider, error) {
 	if token, ok := os.LookupEnv("SLACK_TOKEN"); ok {
 		return StaticToken(token), nil
@@ -41,7 +43,7 @@ func TokenProviderFromEnv() (TokenProvider, error) {
 		return AWSParameter(ssmName, ttl), nil
 	}
 
-	return nil, errors.New("missing SLACK_TOKEN or SLACK_TOKEN_SSM_NAME in environment")
+	return nil, nil
 }
 
 func ssmTTLFromEnv() (time.Duration, error) {
@@ -69,6 +71,13 @@ func StaticToken(token string) TokenProvider {
 // AWS SSM Parameter Store, decrypting it if necessary, and caches the retrieved
 // token value for the provided TTL.
 func AWSParameter(name string, ttl time.Duration) TokenProvider {
+	return TokenProvider(ssmParameter(name, ttl))
+}
+
+// ssmParameter returns a function that retrieves the value of a parameter from
+// the AWS SSM Parameter Store, decrypting it if necessary, and caches the
+// retrieved value for the provided TTL.
+func ssmParameter(name string, ttl time.Duration) func(context.Context) (string, error) {
 	var (
 		lock   = make(chan struct{}, 1)
 		token  string
@@ -97,7 +106,7 @@ func AWSParameter(name string, ttl time.Duration) TokenProvider {
 			WithDecryption: aws.Bool(true),
 		})
 		if err != nil {
-			return "", fmt.Errorf("loading Slack token parameter: %w", err)
+			return "", fmt.Errorf("loading Slack parameter %q: %w", name, err)
 		}
 
 		token = *output.Parameter.Value
@@ -0,0 +1,361 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// DefaultBaseURL is the base URL of the Slack Web API.
+const DefaultBaseURL = "https://slack.com/api/"
+
+// DefaultMembersCacheTTL is the default time for which a Client returned by
+// ClientFromEnv caches the members of user groups and channels.
+const DefaultMembersCacheTTL = 5 * time.Minute
+
+// Client provides access to the Slack Web API methods that the randomizer
+// uses.
+type Client interface {
+	// UserGroupMembers returns the IDs of the users in the user group with the
+	// provided ID.
+	UserGroupMembers(ctx context.Context, usergroup string) (users []string, err error)
+
+	// ChannelMembers returns the IDs of the users in the channel with the
+	// provided ID.
+	ChannelMembers(ctx context.Context, channel string) (users []string, err error)
+
+	// PostMessage posts a message to a channel as the randomizer's bot user.
+	PostMessage(ctx context.Context, msg Message) error
+
+	// OpenView opens a modal view in response to the interaction that provided
+	// triggerID. The view must encode to a Block Kit view object as JSON.
+	OpenView(ctx context.Context, triggerID string, view any) error
+
+	// PublishView publishes the Home tab of the user with the provided ID. The
+	// view must encode to a Block Kit view object as JSON.
+	PublishView(ctx context.Context, userID string, view any) error
+
+	// Reactions returns the reactions to the message with the provided
+	// timestamp in a channel.
+	Reactions(ctx context.Context, channel, timestamp string) ([]Reaction, error)
+}
+
+// Reaction represents the users who reacted to a message with one emoji.
+type Reaction struct {
+	// Name is the name of the emoji, without colons, as in "tada". Reactions
+	// with skin tones have their own names, as in "+1::skin-tone-2".
+	Name string
+	// Users are the IDs of the users who reacted with the emoji.
+	Users []string
+}
+
+// Message represents a message that the randomizer posts through the Web API.
+type Message struct {
+	// Channel is the ID of the channel to post in.
+	Channel string
+	// ThreadTS, if non-empty, is the timestamp of the message to reply to in a
+	// thread.
+	ThreadTS string
+	// Text is the text of the message, in Slack's mrkdwn format.
+	Text string
+}
+
+// ClientFromEnv returns a Client based on available environment variables.
+//
+// If SLACK_BOT_TOKEN is set, it returns a WebClient using that bot token.
+//
+// If SLACK_BOT_TOKEN_SSM_NAME is set, it returns a WebClient that retrieves
+// its bot token from AWS SSM, with the TTL optionally set by
+// SLACK_TOKEN_SSM_TTL.
+//
+// If SLACK_CLIENT_ID is set, for an app that workspaces install through OAuth
+// (see [OAuthConfigFromEnv]), it returns a WebClient that uses the bot token
+// that each workspace granted, as saved in the stores from storeFactory.
+//
+// Otherwise, it returns a nil Client and a nil error, as the randomizer can
+// serve most requests without access to the Web API.
+//
+// The returned client caches the members of user groups and channels for the
+// duration set by SLACK_MEMBERS_CACHE_TTL, or DefaultMembersCacheTTL if unset.
+func ClientFromEnv(storeFactory func(partition string) randomizer.Store) (Client, error) {
+	var tokenProvider TokenProvider
+	if token, ok := os.LookupEnv("SLACK_BOT_TOKEN"); ok {
+		tokenProvider = StaticToken(token)
+	} else if ssmName, ok := os.LookupEnv("SLACK_BOT_TOKEN_SSM_NAME"); ok {
+		ttl, err := ssmTTLFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		tokenProvider = AWSParameter(ssmName, ttl)
+	}
+
+	if _, ok := os.LookupEnv("SLACK_CLIENT_ID"); ok {
+		if tokenProvider != nil {
+			return nil, errors.New("can't use a single Slack bot token with SLACK_CLIENT_ID")
+		}
+		tokenProvider = installedBotToken(storeFactory)
+	}
+
+	if tokenProvider == nil {
+		return nil, nil
+	}
+
+	cacheTTL := DefaultMembersCacheTTL
+	if ttlEnv, ok := os.LookupEnv("SLACK_MEMBERS_CACHE_TTL"); ok {
+		var err error
+		cacheTTL, err = time.ParseDuration(ttlEnv)
+		if err != nil {
+			return nil, fmt.Errorf("SLACK_MEMBERS_CACHE_TTL is not a valid Go duration: %w", err)
+		}
+	}
+
+	return CacheMembers(WebClient{TokenProvider: tokenProvider}, cacheTTL), nil
+}
+
+// WebClient is a Client that calls the Slack Web API over HTTP.
+type WebClient struct {
+	// TokenProvider provides the bot token that authenticates requests to the
+	// Web API.
+	TokenProvider TokenProvider
+	// BaseURL, if non-empty, overrides DefaultBaseURL. It must end with a slash.
+	BaseURL string
+	// HTTPClient, if non-nil, overrides http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// UserGroupMembers implements [Client] with the usergroups.users.list method.
+func (c WebClient) UserGroupMembers(ctx context.Context, usergroup string) ([]string, error) {
+	var response struct {
+		apiResponse
+		Users []string `json:"users"`
+	}
+	err := c.call(ctx, "usergroups.users.list", url.Values{"usergroup": {usergroup}}, &response)
+	return response.Users, err
+}
+
+// ChannelMembers implements [Client] with the conversations.members method,
+// following pagination cursors until it has retrieved every member.
+func (c WebClient) ChannelMembers(ctx context.Context, channel string) ([]string, error) {
+	var (
+		members []string
+		cursor  string
+	)
+	for {
+		var response struct {
+			apiResponse
+			Members []string `json:"members"`
+		}
+		params := url.Values{"channel": {channel}, "limit": {"1000"}}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+		if err := c.call(ctx, "conversations.members", params, &response); err != nil {
+			return nil, err
+		}
+
+		members = append(members, response.Members...)
+		cursor = response.ResponseMetadata.NextCursor
+		if cursor == "" {
+			return members, nil
+		}
+	}
+}
+
+// PostMessage implements [Client] with the chat.postMessage method.
+func (c WebClient) PostMessage(ctx context.Context, msg Message) error {
+	params := url.Values{"channel": {msg.Channel}, "text": {msg.Text}}
+	if msg.ThreadTS != "" {
+		params.Set("thread_ts", msg.ThreadTS)
+	}
+	var response apiResponse
+	return c.call(ctx, "chat.postMessage", params, &response)
+}
+
+// OpenView implements [Client] with the views.open method.
+func (c WebClient) OpenView(ctx context.Context, triggerID string, view any) error {
+	viewJSON, err := json.Marshal(view)
+	if err != nil {
+		return fmt.Errorf("encoding view: %w", err)
+	}
+	params := url.Values{"trigger_id": {triggerID}, "view": {string(viewJSON)}}
+	var response apiResponse
+	return c.call(ctx, "views.open", params, &response)
+}
+
+// PublishView implements [Client] with the views.publish method.
+func (c WebClient) PublishView(ctx context.Context, userID string, view any) error {
+	viewJSON, err := json.Marshal(view)
+	if err != nil {
+		return fmt.Errorf("encoding view: %w", err)
+	}
+	params := url.Values{"user_id": {userID}, "view": {string(viewJSON)}}
+	var response apiResponse
+	return c.call(ctx, "views.publish", params, &response)
+}
+
+// Reactions implements [Client] with the reactions.get method.
+func (c WebClient) Reactions(ctx context.Context, channel, timestamp string) ([]Reaction, error) {
+	var response struct {
+		apiResponse
+		Message struct {
+			Reactions []struct {
+				Name  string   `json:"name"`
+				Users []string `json:"users"`
+			} `json:"reactions"`
+		} `json:"message"`
+	}
+	params := url.Values{"channel": {channel}, "timestamp": {timestamp}, "full": {"true"}}
+	if err := c.call(ctx, "reactions.get", params, &response); err != nil {
+		return nil, err
+	}
+
+	reactions := make([]Reaction, len(response.Message.Reactions))
+	for i, r := range response.Message.Reactions {
+		reactions[i] = Reaction{Name: r.Name, Users: r.Users}
+	}
+	return reactions, nil
+}
+
+// apiResponse represents the fields common to all Web API responses.
+type apiResponse struct {
+	OK               bool   `json:"ok"`
+	Error            string `json:"error"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+func (r apiResponse) err() error {
+	if r.OK {
+		return nil
+	}
+	if r.Error == "" {
+		return errors.New("unknown error")
+	}
+	return errors.New(r.Error)
+}
+
+type apiResult interface {
+	err() error
+}
+
+func (c WebClient) call(ctx context.Context, method string, params url.Values, result apiResult) error {
+	token, err := c.TokenProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("loading Slack bot token: %w", err)
+	}
+	return c.post(ctx, method, params, token, result)
+}
+
+// post calls a Web API method, authenticated by token if it's non-empty.
+func (c WebClient) post(ctx context.Context, method string, params url.Values, token string, result apiResult) error {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("calling %s: HTTP status %s", method, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if err := result.err(); err != nil {
+		return fmt.Errorf("calling %s: %w", method, err)
+	}
+	return nil
+}
+
+// CacheMembers wraps client to cache the members of each user group and
+// channel for the provided TTL. Calls to any other methods of the returned
+// Client pass through to the original.
+func CacheMembers(client Client, ttl time.Duration) Client {
+	return &membersCache{
+		Client:  client,
+		ttl:     ttl,
+		entries: make(map[string]membersEntry),
+		now:     time.Now,
+	}
+}
+
+type membersCache struct {
+	Client
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]membersEntry
+}
+
+type membersEntry struct {
+	members []string
+	expiry  time.Time
+}
+
+func (c *membersCache) UserGroupMembers(ctx context.Context, usergroup string) ([]string, error) {
+	return c.get("usergroup:"+usergroup, func() ([]string, error) {
+		return c.Client.UserGroupMembers(ctx, usergroup)
+	})
+}
+
+func (c *membersCache) ChannelMembers(ctx context.Context, channel string) ([]string, error) {
+	return c.get("channel:"+channel, func() ([]string, error) {
+		return c.Client.ChannelMembers(ctx, channel)
+	})
+}
+
+func (c *membersCache) get(key string, load func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiry) {
+		return slices.Clone(entry.members), nil
+	}
+
+	// We don't hold the lock while loading, so concurrent requests for the same
+	// key might each call the Web API. That's fine, as the last one to finish
+	// will simply overwrite the others.
+	members, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expiry) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = membersEntry{members: slices.Clone(members), expiry: now.Add(c.ttl)}
+	return members, nil
+}
@@ -0,0 +1,288 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+// fakeSlack is a stand-in for the Slack Web API, serving a fixed set of user
+// groups, channel members, and message reactions, and recording posted
+// messages and opened and published views. It also installs the app into the
+// "T1" workspace in exchange for OAuthCode.
+type fakeSlack struct {
+	Token      string
+	UserGroups map[string][]string
+	Channels   map[string][]string
+	Reactions  map[string][]Reaction // by "CHANNEL/TS"
+	OAuthCode  string
+
+	mu       sync.Mutex
+	calls    []string
+	messages []Message
+	views    []string
+	homes    map[string]string
+}
+
+// start runs an HTTP server for the fake API, and returns a WebClient that
+// calls it.
+func (f *fakeSlack) start(t *testing.T) WebClient {
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return WebClient{
+		TokenProvider: StaticToken(f.Token),
+		BaseURL:       srv.URL + "/api/",
+		HTTPClient:    srv.Client(),
+	}
+}
+
+func (f *fakeSlack) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+func (f *fakeSlack) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.messages)
+}
+
+func (f *fakeSlack) Views() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.views)
+}
+
+// Home returns the Home tab view last published for a user.
+func (f *fakeSlack) Home(userID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.homes[userID]
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+	f.mu.Lock()
+	f.calls = append(f.calls, method)
+	f.mu.Unlock()
+
+	if method == "oauth.v2.access" {
+		r.ParseForm()
+		if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" ||
+			r.PostForm.Get("code") != f.OAuthCode {
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "invalid_code"})
+			return
+		}
+		writeFakeResponse(w, map[string]any{
+			"ok":           true,
+			"access_token": f.Token,
+			"token_type":   "bot",
+			"team":         map[string]string{"id": "T1", "name": "Test Team"},
+		})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+f.Token {
+		writeFakeResponse(w, map[string]any{"ok": false, "error": "invalid_auth"})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch method {
+	case "usergroups.users.list":
+		users, ok := f.UserGroups[r.PostForm.Get("usergroup")]
+		if !ok {
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "no_such_subteam"})
+			return
+		}
+		writeFakeResponse(w, map[string]any{"ok": true, "users": users})
+
+	case "conversations.members":
+		members, ok := f.Channels[r.PostForm.Get("channel")]
+		if !ok {
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "channel_not_found"})
+			return
+		}
+		// Return one member per page to exercise pagination.
+		var start int
+		if cursor := r.PostForm.Get("cursor"); cursor != "" {
+			start = slices.Index(members, cursor)
+		}
+		var next string
+		if start+1 < len(members) {
+			next = members[start+1]
+		}
+		writeFakeResponse(w, map[string]any{
+			"ok":                true,
+			"members":           members[start : start+1],
+			"response_metadata": map[string]string{"next_cursor": next},
+		})
+
+	case "chat.postMessage":
+		f.mu.Lock()
+		f.messages = append(f.messages, Message{
+			Channel:  r.PostForm.Get("channel"),
+			ThreadTS: r.PostForm.Get("thread_ts"),
+			Text:     r.PostForm.Get("text"),
+		})
+		f.mu.Unlock()
+		writeFakeResponse(w, map[string]any{"ok": true})
+
+	case "views.open":
+		if r.PostForm.Get("trigger_id") == "" {
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "invalid_trigger_id"})
+			return
+		}
+		f.mu.Lock()
+		f.views = append(f.views, r.PostForm.Get("view"))
+		f.mu.Unlock()
+		writeFakeResponse(w, map[string]any{"ok": true})
+
+	case "views.publish":
+		f.mu.Lock()
+		if f.homes == nil {
+			f.homes = make(map[string]string)
+		}
+		f.homes[r.PostForm.Get("user_id")] = r.PostForm.Get("view")
+		f.mu.Unlock()
+		writeFakeResponse(w, map[string]any{"ok": true})
+
+	case "reactions.get":
+		reactions, ok := f.Reactions[r.PostForm.Get("channel")+"/"+r.PostForm.Get("timestamp")]
+		if !ok {
+			writeFakeResponse(w, map[string]any{"ok": false, "error": "message_not_found"})
+			return
+		}
+		var message struct {
+			Reactions []map[string]any `json:"reactions"`
+		}
+		for _, reaction := range reactions {
+			message.Reactions = append(message.Reactions, map[string]any{
+				"name":  reaction.Name,
+				"users": reaction.Users,
+				"count": len(reaction.Users),
+			})
+		}
+		writeFakeResponse(w, map[string]any{"ok": true, "type": "message", "message": message})
+
+	default:
+		writeFakeResponse(w, map[string]any{"ok": false, "error": "unknown_method"})
+	}
+}
+
+func writeFakeResponse(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestWebClient(t *testing.T) {
+	fake := &fakeSlack{
+		Token:      "xoxb-test",
+		UserGroups: map[string][]string{"S123": {"U1", "U2"}},
+		Channels:   map[string][]string{"C123": {"U1", "U2", "U3"}},
+	}
+	client := fake.start(t)
+	ctx := context.Background()
+
+	users, err := client.UserGroupMembers(ctx, "S123")
+	if err != nil {
+		t.Fatalf("unexpected error getting user group members: %v", err)
+	}
+	if want := []string{"U1", "U2"}; !slices.Equal(users, want) {
+		t.Errorf("got user group members %v, want %v", users, want)
+	}
+
+	members, err := client.ChannelMembers(ctx, "C123")
+	if err != nil {
+		t.Fatalf("unexpected error getting channel members: %v", err)
+	}
+	if want := []string{"U1", "U2", "U3"}; !slices.Equal(members, want) {
+		t.Errorf("got channel members %v, want %v", members, want)
+	}
+
+	_, err = client.UserGroupMembers(ctx, "S404")
+	if err == nil || !strings.Contains(err.Error(), "no_such_subteam") {
+		t.Errorf("got error %v for missing user group, want no_such_subteam", err)
+	}
+
+	client.TokenProvider = StaticToken("xoxb-wrong")
+	_, err = client.ChannelMembers(ctx, "C123")
+	if err == nil || !strings.Contains(err.Error(), "invalid_auth") {
+		t.Errorf("got error %v for wrong token, want invalid_auth", err)
+	}
+}
+
+func TestCacheMembers(t *testing.T) {
+	fake := &fakeSlack{
+		Token:      "xoxb-test",
+		UserGroups: map[string][]string{"S123": {"U1", "U2"}},
+	}
+	client := CacheMembers(fake.start(t), time.Minute).(*membersCache)
+
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	for range 2 {
+		if _, err := client.UserGroupMembers(context.Background(), "S123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls := len(fake.Calls()); calls != 1 {
+		t.Errorf("made %d API calls before expiry, want 1", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := client.UserGroupMembers(context.Background(), "S123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls := len(fake.Calls()); calls != 2 {
+		t.Errorf("made %d API calls after expiry, want 2", calls)
+	}
+}
+
+func TestMemberExpansion(t *testing.T) {
+	fake := &fakeSlack{
+		Token:      "xoxb-test",
+		UserGroups: map[string][]string{"S123": {"U1", "U2"}},
+		Channels:   map[string][]string{"C12345678": {"U3"}},
+	}
+	app := App{
+		TokenProvider: StaticToken("right"),
+		StoreFactory:  func(_ string) randomizer.Store { return make(rndtest.Store) },
+		Client:        fake.start(t),
+	}
+
+	headers := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+	params := makeTestParams("<!subteam^S123|@backend-team> /members")
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+	req.Header = headers
+	app.ServeHTTP(resp, req)
+
+	var body response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Type != typeInChannel {
+		t.Errorf("got response type %q, want %q: %s", body.Type, typeInChannel, body.Text)
+	}
+	for _, user := range []string{"<@U1>", "<@U2>", "<@U3>"} {
+		if !strings.Contains(body.Text, user) {
+			t.Errorf("response missing %s: %s", user, body.Text)
+		}
+	}
+}
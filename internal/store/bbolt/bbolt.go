@@ -7,11 +7,41 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+// aliasPrefix, awayPrefix, and rateLimitPrefix are the prefixes of the keys
+// that hold aliases, away options, and rate limits in a Store's bucket, and
+// botTokenKey is the key that holds a Slack bot token.
+const (
+	aliasPrefix     = "/alias/"
+	awayPrefix      = "/away/"
+	rateLimitPrefix = "/ratelimit/"
+	botTokenKey     = "/bottoken"
 )
 
 // Store is a store backed by a bbolt database.
+//
+// Each Store keeps its groups in a single bucket, keyed by group name, with
+// each [randomizer.Group] encoded by [encoding/gob]. (Older versions of the
+// randomizer encoded only each group's options, which Store can still read.)
+//
+// Aliases are stored in the same bucket under keys of the form "/alias/NAME",
+// with the name of the target group as the value, and away options under keys
+// of the form "/away/OPTION", with the RFC 3339 time at which the option
+// returns as the value. A Slack bot token, if any, is stored under the
+// "/bottoken" key, and the state of each Slack rate limit bucket under a key of
+// the form "/ratelimit/KEY", with the number of tokens, the RFC 3339 time at
+// which they were counted, and the RFC 3339 time at which the bucket refills
+// completely, separated by spaces. Saving a bucket deletes the others in the
+// same partition that have refilled. Group names can't conflict with these
+// keys, as the randomizer reserves the "/" prefix for flags.
 type Store struct {
 	db     *bolt.DB
 	bucket string
@@ -38,7 +68,9 @@ func (b Store) List(_ context.Context) (groups []string, err error) {
 		}
 
 		return bucket.ForEach(func(k, _ []byte) error {
-			groups = append(groups, string(k))
+			if !bytes.HasPrefix(k, []byte("/")) {
+				groups = append(groups, string(k))
+			}
 			return nil
 		})
 	})
@@ -46,7 +78,42 @@ func (b Store) List(_ context.Context) (groups []string, err error) {
 }
 
 // Get obtains the options in a single named group.
-func (b Store) Get(_ context.Context, name string) (options []string, err error) {
+func (b Store) Get(ctx context.Context, name string) ([]string, error) {
+	group, err := b.GetGroup(ctx, name)
+	return group.Options, err
+}
+
+// Put saves the provided options into a named group, with no metadata.
+func (b Store) Put(ctx context.Context, name string, options []string) error {
+	return b.PutGroup(ctx, name, randomizer.Group{Options: options})
+}
+
+// ListGroups obtains all stored groups with their metadata.
+func (b Store) ListGroups(_ context.Context) (groups map[string]randomizer.Group, err error) {
+	groups = make(map[string]randomizer.Group)
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			if bytes.HasPrefix(k, []byte("/")) {
+				return nil
+			}
+			group, err := DecodeGroup(v)
+			if err != nil {
+				return fmt.Errorf("decoding group %q: %w", k, err)
+			}
+			groups[string(k)] = group
+			return nil
+		})
+	})
+	return
+}
+
+// GetGroup obtains a single named group with its metadata.
+func (b Store) GetGroup(_ context.Context, name string) (group randomizer.Group, err error) {
 	err = b.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(b.bucket))
 		if bucket == nil {
@@ -58,8 +125,7 @@ func (b Store) Get(_ context.Context, name string) (options []string, err error)
 			return nil
 		}
 
-		decoder := gob.NewDecoder(bytes.NewReader(result))
-		err := decoder.Decode(&options)
+		group, err = DecodeGroup(result)
 		if err != nil {
 			return fmt.Errorf("decoding group %q: %w", name, err)
 		}
@@ -69,8 +135,8 @@ func (b Store) Get(_ context.Context, name string) (options []string, err error)
 	return
 }
 
-// Put saves the provided options into a named group.
-func (b Store) Put(_ context.Context, name string, options []string) error {
+// PutGroup saves the provided group with its metadata.
+func (b Store) PutGroup(_ context.Context, name string, group randomizer.Group) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucket))
 		if err != nil {
@@ -79,9 +145,9 @@ func (b Store) Put(_ context.Context, name string, options []string) error {
 
 		var result bytes.Buffer
 		encoder := gob.NewEncoder(&result)
-		err = encoder.Encode(&options)
+		err = encoder.Encode(&group)
 		if err != nil {
-			return fmt.Errorf("encoding group %q (%v): %w", name, options, err)
+			return fmt.Errorf("encoding group %q (%v): %w", name, group.Options, err)
 		}
 
 		err = bucket.Put([]byte(name), result.Bytes())
@@ -93,6 +159,22 @@ func (b Store) Put(_ context.Context, name string, options []string) error {
 	})
 }
 
+// DecodeGroup decodes the value of a group in a Store's bucket, which may
+// have been written by an older version of the randomizer without metadata.
+func DecodeGroup(value []byte) (group randomizer.Group, err error) {
+	err = gob.NewDecoder(bytes.NewReader(value)).Decode(&group)
+	if err == nil {
+		return group, nil
+	}
+
+	var options []string
+	if gob.NewDecoder(bytes.NewReader(value)).Decode(&options) == nil {
+		return randomizer.Group{Options: options}, nil
+	}
+
+	return randomizer.Group{}, err
+}
+
 // Delete removes the named group from the store.
 func (b Store) Delete(_ context.Context, name string) (existed bool, err error) {
 	err = b.db.Update(func(tx *bolt.Tx) error {
@@ -114,3 +196,301 @@ func (b Store) Delete(_ context.Context, name string) (existed bool, err error)
 	})
 	return
 }
+
+// ListAliases obtains the set of stored aliases.
+func (b Store) ListAliases(_ context.Context) (aliases map[string]string, err error) {
+	aliases = make(map[string]string)
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		prefix := []byte(aliasPrefix)
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			aliases[string(k[len(prefix):])] = string(v)
+		}
+		return nil
+	})
+	return
+}
+
+// GetAlias obtains the name of the group that a single alias refers to.
+func (b Store) GetAlias(_ context.Context, alias string) (group string, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		group = string(bucket.Get([]byte(aliasPrefix + alias)))
+		return nil
+	})
+	return
+}
+
+// PutAlias saves an alias for the named group.
+func (b Store) PutAlias(_ context.Context, alias, group string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucket))
+		if err != nil {
+			return fmt.Errorf("creating bucket: %w", err)
+		}
+
+		err = bucket.Put([]byte(aliasPrefix+alias), []byte(group))
+		if err != nil {
+			return fmt.Errorf("writing alias %q: %w", alias, err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteAlias removes the named alias from the store.
+func (b Store) DeleteAlias(_ context.Context, alias string) (existed bool, err error) {
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+		key := []byte(aliasPrefix + alias)
+		if bucket.Get(key) == nil {
+			return nil
+		}
+
+		existed = true
+		err := bucket.Delete(key)
+		if err != nil {
+			return fmt.Errorf("deleting alias %q: %w", alias, err)
+		}
+
+		return nil
+	})
+	return
+}
+
+// ListAway obtains the set of options that are away.
+func (b Store) ListAway(_ context.Context) (away map[string]time.Time, err error) {
+	away = make(map[string]time.Time)
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		prefix := []byte(awayPrefix)
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			until, err := time.Parse(time.RFC3339, string(v))
+			if err != nil {
+				return fmt.Errorf("decoding away option %q: %w", k[len(prefix):], err)
+			}
+			away[string(k[len(prefix):])] = until
+		}
+		return nil
+	})
+	return
+}
+
+// PutAway records that an option is away until the provided time.
+func (b Store) PutAway(_ context.Context, option string, until time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucket))
+		if err != nil {
+			return fmt.Errorf("creating bucket: %w", err)
+		}
+
+		err = bucket.Put([]byte(awayPrefix+option), []byte(until.UTC().Format(time.RFC3339)))
+		if err != nil {
+			return fmt.Errorf("writing away option %q: %w", option, err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteAway removes the record of an away option from the store.
+func (b Store) DeleteAway(_ context.Context, option string) (existed bool, err error) {
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+		key := []byte(awayPrefix + option)
+		if bucket.Get(key) == nil {
+			return nil
+		}
+
+		existed = true
+		err := bucket.Delete(key)
+		if err != nil {
+			return fmt.Errorf("deleting away option %q: %w", option, err)
+		}
+
+		return nil
+	})
+	return
+}
+
+// GetBotToken obtains the Slack bot token saved in the store, or returns an
+// empty string if none is saved.
+func (b Store) GetBotToken(_ context.Context) (token string, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+		token = string(bucket.Get([]byte(botTokenKey)))
+		return nil
+	})
+	return
+}
+
+// PutBotToken saves a Slack bot token in the store, overwriting any previous
+// token.
+func (b Store) PutBotToken(_ context.Context, token string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucket))
+		if err != nil {
+			return fmt.Errorf("creating bucket: %w", err)
+		}
+
+		err = bucket.Put([]byte(botTokenKey), []byte(token))
+		if err != nil {
+			return fmt.Errorf("writing bot token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteBotToken removes the Slack bot token from the store.
+func (b Store) DeleteBotToken(_ context.Context) (existed bool, err error) {
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+		key := []byte(botTokenKey)
+		if bucket.Get(key) == nil {
+			return nil
+		}
+
+		existed = true
+		err := bucket.Delete(key)
+		if err != nil {
+			return fmt.Errorf("deleting bot token: %w", err)
+		}
+
+		return nil
+	})
+	return
+}
+
+// GetRateLimit obtains the state of the named Slack rate limit bucket.
+func (b Store) GetRateLimit(_ context.Context, key string) (tokens float64, at time.Time, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(b.bucket))
+		if bucket == nil {
+			return nil
+		}
+		tokens, at, _, err = decodeRateLimit(bucket.Get([]byte(rateLimitPrefix + key)))
+		return err
+	})
+	return
+}
+
+// PutRateLimit saves the state of the named Slack rate limit bucket, if its
+// previous state was counted at prevAt, and deletes any buckets that have
+// refilled completely by at.
+func (b Store) PutRateLimit(_ context.Context, key string, tokens float64, at, prevAt, expires time.Time) (saved bool, err error) {
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(b.bucket))
+		if err != nil {
+			return fmt.Errorf("creating bucket: %w", err)
+		}
+
+		_, currentAt, _, err := decodeRateLimit(bucket.Get([]byte(rateLimitPrefix + key)))
+		if err != nil {
+			return err
+		}
+		if !currentAt.Equal(prevAt) {
+			return nil
+		}
+
+		if err := pruneRateLimits(bucket, at); err != nil {
+			return err
+		}
+
+		value := strconv.FormatFloat(tokens, 'g', -1, 64) + " " +
+			at.UTC().Format(time.RFC3339Nano) + " " +
+			expires.UTC().Format(time.RFC3339Nano)
+		err = bucket.Put([]byte(rateLimitPrefix+key), []byte(value))
+		if err != nil {
+			return fmt.Errorf("writing rate limit %q: %w", key, err)
+		}
+
+		saved = true
+		return nil
+	})
+	return
+}
+
+// pruneRateLimits deletes the rate limit buckets that have refilled completely
+// by now.
+func pruneRateLimits(bucket *bolt.Bucket, now time.Time) error {
+	var expired [][]byte
+	c := bucket.Cursor()
+	prefix := []byte(rateLimitPrefix)
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		_, _, expires, err := decodeRateLimit(v)
+		if err != nil {
+			return err
+		}
+		if !now.Before(expires) {
+			expired = append(expired, k)
+		}
+	}
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return fmt.Errorf("deleting expired rate limit: %w", err)
+		}
+	}
+	return nil
+}
+
+func decodeRateLimit(value []byte) (tokens float64, at, expires time.Time, err error) {
+	if value == nil {
+		return 0, time.Time{}, time.Time{}, nil
+	}
+	fields := strings.Fields(string(value))
+	if len(fields) != 3 {
+		return 0, time.Time{}, time.Time{}, fmt.Errorf("decoding rate limit: got %d fields, want 3", len(fields))
+	}
+	tokens, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, time.Time{}, time.Time{}, fmt.Errorf("decoding rate limit tokens: %w", err)
+	}
+	at, err = time.Parse(time.RFC3339Nano, fields[1])
+	if err != nil {
+		return 0, time.Time{}, time.Time{}, fmt.Errorf("decoding rate limit time: %w", err)
+	}
+	expires, err = time.Parse(time.RFC3339Nano, fields[2])
+	if err != nil {
+		return 0, time.Time{}, time.Time{}, fmt.Errorf("decoding rate limit expiry: %w", err)
+	}
+	return tokens, at, expires, nil
+}
+
+// ListPartitions obtains the names of the buckets in the database, each of
+// which holds the groups of one partition.
+func (b Store) ListPartitions(_ context.Context) (partitions []string, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			partitions = append(partitions, string(name))
+			return nil
+		})
+	})
+	return
+}
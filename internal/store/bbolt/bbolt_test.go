@@ -0,0 +1,239 @@
+package bbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+func TestLegacyGroups(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "randomizer.db"), 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Older versions of the randomizer encoded groups as plain lists of options.
+	var legacy bytes.Buffer
+	if err := gob.NewEncoder(&legacy).Encode([]string{"one", "two"}); err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte("Groups"))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte("legacy"), legacy.Bytes())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := New(db, "Groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	modern := randomizer.Group{
+		Options:     []string{"three", "four"},
+		Description: "Modern",
+		Created:     time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := store.PutGroup(ctx, "modern", modern); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutAlias(ctx, "old", "legacy"); err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := store.Get(ctx, "legacy")
+	if err != nil || !reflect.DeepEqual(options, []string{"one", "two"}) {
+		t.Errorf("Get(legacy) = %v, %v", options, err)
+	}
+
+	groups, err := store.ListGroups(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]randomizer.Group{
+		"legacy": {Options: []string{"one", "two"}},
+		"modern": modern,
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("ListGroups() = %+v, want %+v", groups, want)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil || !reflect.DeepEqual(names, []string{"legacy", "modern"}) {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+}
+
+func TestAway(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "randomizer.db"), 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := New(db, "Groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	until := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.Put(ctx, "test", []string{"alice", "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutAway(ctx, "alice", until); err != nil {
+		t.Fatal(err)
+	}
+
+	away, err := store.ListAway(ctx)
+	if err != nil || !reflect.DeepEqual(away, map[string]time.Time{"alice": until}) {
+		t.Errorf("ListAway() = %v, %v", away, err)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil || !reflect.DeepEqual(names, []string{"test"}) {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+
+	existed, err := store.DeleteAway(ctx, "alice")
+	if err != nil || !existed {
+		t.Errorf("DeleteAway(alice) = %v, %v", existed, err)
+	}
+	existed, err = store.DeleteAway(ctx, "alice")
+	if err != nil || existed {
+		t.Errorf("second DeleteAway(alice) = %v, %v", existed, err)
+	}
+}
+
+func TestBotToken(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "randomizer.db"), 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := New(db, "T123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if token, err := store.GetBotToken(ctx); err != nil || token != "" {
+		t.Errorf("GetBotToken() before saving = %q, %v", token, err)
+	}
+	if err := store.PutBotToken(ctx, "xoxb-test"); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := store.GetBotToken(ctx); err != nil || token != "xoxb-test" {
+		t.Errorf("GetBotToken() = %q, %v", token, err)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil || len(names) > 0 {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+
+	existed, err := store.DeleteBotToken(ctx)
+	if err != nil || !existed {
+		t.Errorf("DeleteBotToken() = %v, %v", existed, err)
+	}
+	if token, err := store.GetBotToken(ctx); err != nil || token != "" {
+		t.Errorf("GetBotToken() after deleting = %q, %v", token, err)
+	}
+}
+
+func TestListPartitions(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "randomizer.db"), 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, partition := range []string{"C2", "C1"} {
+		store, err := New(db, partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Put(ctx, "test", []string{"one", "two"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := New(db, "C3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	partitions, err := store.ListPartitions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"C1", "C2"}; !reflect.DeepEqual(partitions, want) {
+		t.Errorf("ListPartitions() = %v, want %v", partitions, want)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "randomizer.db"), 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := New(db, "T123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if tokens, at, err := store.GetRateLimit(ctx, "user:U1"); err != nil || tokens != 0 || !at.IsZero() {
+		t.Errorf("GetRateLimit() before saving = %v, %v, %v", tokens, at, err)
+	}
+
+	first := time.Date(2026, 10, 1, 12, 0, 0, 123e6, time.UTC)
+	if saved, err := store.PutRateLimit(ctx, "user:U1", 4.5, first, time.Time{}, first.Add(time.Minute)); err != nil || !saved {
+		t.Fatalf("PutRateLimit() for new bucket = %v, %v", saved, err)
+	}
+	if tokens, at, err := store.GetRateLimit(ctx, "user:U1"); err != nil || tokens != 4.5 || !at.Equal(first) {
+		t.Errorf("GetRateLimit() = %v, %v, %v", tokens, at, err)
+	}
+
+	second := first.Add(time.Second)
+	if saved, err := store.PutRateLimit(ctx, "user:U1", 3, second, time.Time{}, second.Add(time.Minute)); err != nil || saved {
+		t.Errorf("PutRateLimit() with stale time = %v, %v", saved, err)
+	}
+	if saved, err := store.PutRateLimit(ctx, "user:U1", 3, second, first, second.Add(time.Minute)); err != nil || !saved {
+		t.Errorf("PutRateLimit() with current time = %v, %v", saved, err)
+	}
+
+	// Saving another bucket after the first one refills deletes the first.
+	third := second.Add(time.Minute)
+	if saved, err := store.PutRateLimit(ctx, "channel:C1", 9, third, time.Time{}, third.Add(time.Minute)); err != nil || !saved {
+		t.Errorf("PutRateLimit() for second bucket = %v, %v", saved, err)
+	}
+	if tokens, at, err := store.GetRateLimit(ctx, "user:U1"); err != nil || tokens != 0 || !at.IsZero() {
+		t.Errorf("GetRateLimit() for expired bucket = %v, %v, %v", tokens, at, err)
+	}
+	if tokens, at, err := store.GetRateLimit(ctx, "channel:C1"); err != nil || tokens != 9 || !at.Equal(third) {
+		t.Errorf("GetRateLimit() for second bucket = %v, %v, %v", tokens, at, err)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil || len(names) > 0 {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+}
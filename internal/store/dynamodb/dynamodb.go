@@ -5,16 +5,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
 )
 
 const (
 	partitionKey = "Partition"
 	groupKey     = "Group"
 	itemsKey     = "Items"
+	targetKey    = "Target"
+	untilKey     = "Until"
+	expiresKey   = "Expires"
+	tokenKey     = "Token"
+	tokensKey    = "Tokens"
+	atKey        = "At"
+
+	descriptionKey = "Description"
+	tagsKey        = "Tags"
+	creatorKey     = "Creator"
+	createdKey     = "Created"
+	updatedKey     = "Updated"
+)
+
+// aliasPrefix, awayPrefix, and rateLimitPrefix are the prefixes of the sort
+// keys of items that hold aliases, away options, and rate limits, and
+// botTokenGroup is the sort key of the item that holds a Slack bot token.
+const (
+	aliasPrefix     = "/alias/"
+	awayPrefix      = "/away/"
+	rateLimitPrefix = "/ratelimit/"
+	botTokenGroup   = "/bottoken"
 )
 
 // Store is a store backed by a pre-existing Amazon DynamoDB table.
@@ -23,6 +51,29 @@ const (
 // partition key named "Partition" and a sort key named "Group", both
 // string-valued. Items in each row are stored in a string set attribute named
 // "Items".
+//
+// Group metadata is stored in optional attributes alongside the items: string
+// attributes named "Description" and "Creator", a string set attribute named
+// "Tags", and RFC 3339 timestamps in string attributes named "Created" and
+// "Updated".
+//
+// Aliases are stored in the same partition as groups, under sort keys of the
+// form "/alias/NAME", with the name of the target group in a string attribute
+// named "Target". Options that are away are stored under sort keys of the form
+// "/away/OPTION", with the RFC 3339 time at which the option returns in a
+// string attribute named "Until", and the same time in seconds since the Unix
+// epoch in a number attribute named "Expires". You can enable [Time to Live]
+// on the "Expires" attribute to have DynamoDB clean up these items after the
+// option returns. A Slack bot token, if any, is stored under the "/bottoken"
+// sort key, in a string attribute named "Token". The state of each Slack rate
+// limit bucket is stored under a sort key of the form "/ratelimit/KEY", with
+// the number of tokens in a number attribute named "Tokens", the RFC 3339 time
+// at which they were counted in a string attribute named "At", and the time at
+// which the bucket refills completely in the same "Expires" attribute, so that
+// Time to Live cleans these items up too. Group names can't conflict with these
+// keys, as the randomizer reserves the "/" prefix for flags.
+//
+// [Time to Live]: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/TTL.html
 type Store struct {
 	db        *dynamodb.Client
 	table     string
@@ -79,13 +130,15 @@ func (s Store) List(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("listing groups for %q from table %q: %w", s.partition, s.table, err)
 	}
 
-	list := make([]string, len(result.Items))
-	for i, item := range result.Items {
+	list := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
 		v, ok := item[groupKey].(*types.AttributeValueMemberS)
 		if !ok {
 			return nil, fmt.Errorf("invalid type %T in group names", item[groupKey])
 		}
-		list[i] = v.Value
+		if !strings.HasPrefix(v.Value, "/") {
+			list = append(list, v.Value)
+		}
 	}
 	return list, nil
 }
@@ -127,16 +180,106 @@ func (s Store) Get(ctx context.Context, name string) ([]string, error) {
 }
 
 // Put saves the provided options into a named group for this Store's
-// partition.
+// partition, with no metadata.
 func (s Store) Put(ctx context.Context, name string, options []string) error {
-	_, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+	return s.PutGroup(ctx, name, randomizer.Group{Options: options})
+}
+
+// ListGroups obtains all stored groups with their metadata for this Store's
+// partition.
+func (s Store) ListGroups(ctx context.Context) (map[string]randomizer.Group, error) {
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(
+			expression.KeyEqual(
+				expression.Key(partitionKey), expression.Value(s.partition),
+			),
+		).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("building expression: %w", err)
+	}
+
+	result, err := s.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 &s.table,
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing groups for %q from table %q: %w", s.partition, s.table, err)
+	}
+
+	groups := make(map[string]randomizer.Group, len(result.Items))
+	for _, item := range result.Items {
+		name, ok := item[groupKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T in group names", item[groupKey])
+		}
+		if strings.HasPrefix(name.Value, "/") {
+			continue
+		}
+		group, err := decodeGroup(item)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", name.Value, err)
+		}
+		groups[name.Value] = group
+	}
+	return groups, nil
+}
+
+// GetGroup obtains a single named group with its metadata from this Store's
+// partition.
+func (s Store) GetGroup(ctx context.Context, name string) (randomizer.Group, error) {
+	result, err := s.db.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: &s.table,
-		Item: map[string]types.AttributeValue{
+		Key: map[string]types.AttributeValue{
 			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
 			groupKey:     &types.AttributeValueMemberS{Value: name},
-			itemsKey:     &types.AttributeValueMemberSS{Value: options},
 		},
 	})
+	if err != nil {
+		return randomizer.Group{}, fmt.Errorf("getting %q for %q from table %q: %w", name, s.partition, s.table, err)
+	}
+
+	if len(result.Item) == 0 {
+		return randomizer.Group{}, nil
+	}
+
+	group, err := decodeGroup(result.Item)
+	if err != nil {
+		return randomizer.Group{}, fmt.Errorf("decoding %q: %w", name, err)
+	}
+	return group, nil
+}
+
+// PutGroup saves the provided group with its metadata into this Store's
+// partition.
+func (s Store) PutGroup(ctx context.Context, name string, group randomizer.Group) error {
+	item := map[string]types.AttributeValue{
+		partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+		groupKey:     &types.AttributeValueMemberS{Value: name},
+		itemsKey:     &types.AttributeValueMemberSS{Value: group.Options},
+	}
+	if group.Description != "" {
+		item[descriptionKey] = &types.AttributeValueMemberS{Value: group.Description}
+	}
+	if len(group.Tags) > 0 {
+		item[tagsKey] = &types.AttributeValueMemberSS{Value: group.Tags}
+	}
+	if group.Creator != "" {
+		item[creatorKey] = &types.AttributeValueMemberS{Value: group.Creator}
+	}
+	if !group.Created.IsZero() {
+		item[createdKey] = &types.AttributeValueMemberS{Value: group.Created.Format(time.RFC3339Nano)}
+	}
+	if !group.Updated.IsZero() {
+		item[updatedKey] = &types.AttributeValueMemberS{Value: group.Updated.Format(time.RFC3339Nano)}
+	}
+
+	_, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item:      item,
+	})
 	if err != nil {
 		return fmt.Errorf("saving %q for %q to table %q: %w", name, s.partition, s.table, err)
 	}
@@ -144,6 +287,36 @@ func (s Store) Put(ctx context.Context, name string, options []string) error {
 	return nil
 }
 
+func decodeGroup(item map[string]types.AttributeValue) (group randomizer.Group, err error) {
+	items, ok := item[itemsKey].(*types.AttributeValueMemberSS)
+	if !ok {
+		return randomizer.Group{}, fmt.Errorf("invalid type %T in group items", item[itemsKey])
+	}
+	group.Options = items.Value
+
+	if v, ok := item[descriptionKey].(*types.AttributeValueMemberS); ok {
+		group.Description = v.Value
+	}
+	if v, ok := item[tagsKey].(*types.AttributeValueMemberSS); ok {
+		group.Tags = v.Value
+	}
+	if v, ok := item[creatorKey].(*types.AttributeValueMemberS); ok {
+		group.Creator = v.Value
+	}
+	if v, ok := item[createdKey].(*types.AttributeValueMemberS); ok {
+		if group.Created, err = time.Parse(time.RFC3339Nano, v.Value); err != nil {
+			return randomizer.Group{}, fmt.Errorf("invalid creation time: %w", err)
+		}
+	}
+	if v, ok := item[updatedKey].(*types.AttributeValueMemberS); ok {
+		if group.Updated, err = time.Parse(time.RFC3339Nano, v.Value); err != nil {
+			return randomizer.Group{}, fmt.Errorf("invalid update time: %w", err)
+		}
+	}
+
+	return group, nil
+}
+
 // Delete removes the named group from this Store's partition.
 func (s Store) Delete(ctx context.Context, name string) (bool, error) {
 	result, err := s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
@@ -161,3 +334,383 @@ func (s Store) Delete(ctx context.Context, name string) (bool, error) {
 	existed := len(result.Attributes) > 0
 	return existed, nil
 }
+
+// ListAliases obtains the stored aliases for this Store's partition.
+func (s Store) ListAliases(ctx context.Context) (map[string]string, error) {
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(
+			expression.KeyEqual(
+				expression.Key(partitionKey), expression.Value(s.partition),
+			).And(
+				expression.KeyBeginsWith(expression.Key(groupKey), aliasPrefix),
+			),
+		).
+		WithProjection(expression.NamesList(
+			expression.Name(groupKey), expression.Name(targetKey),
+		)).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("building expression: %w", err)
+	}
+
+	result, err := s.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 &s.table,
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases for %q from table %q: %w", s.partition, s.table, err)
+	}
+
+	aliases := make(map[string]string, len(result.Items))
+	for _, item := range result.Items {
+		alias, ok := item[groupKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T in alias names", item[groupKey])
+		}
+		target, ok := item[targetKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T in alias targets", item[targetKey])
+		}
+		aliases[strings.TrimPrefix(alias.Value, aliasPrefix)] = target.Value
+	}
+	return aliases, nil
+}
+
+// GetAlias obtains the name of the group that a single alias refers to from
+// this Store's partition.
+func (s Store) GetAlias(ctx context.Context, alias string) (string, error) {
+	expr, err := expression.NewBuilder().
+		WithProjection(expression.NamesList(
+			expression.Name(targetKey),
+		)).
+		Build()
+	if err != nil {
+		return "", fmt.Errorf("building expression: %w", err)
+	}
+
+	result, err := s.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: aliasPrefix + alias},
+		},
+		ProjectionExpression:     expr.Projection(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting alias %q for %q from table %q: %w", alias, s.partition, s.table, err)
+	}
+
+	if len(result.Item) == 0 {
+		return "", nil
+	}
+
+	v, ok := result.Item[targetKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", fmt.Errorf("invalid type %T in alias target", result.Item[targetKey])
+	}
+
+	return v.Value, nil
+}
+
+// PutAlias saves an alias for the named group into this Store's partition.
+func (s Store) PutAlias(ctx context.Context, alias, group string) error {
+	_, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: aliasPrefix + alias},
+			targetKey:    &types.AttributeValueMemberS{Value: group},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("saving alias %q for %q to table %q: %w", alias, s.partition, s.table, err)
+	}
+
+	return nil
+}
+
+// DeleteAlias removes the named alias from this Store's partition.
+func (s Store) DeleteAlias(ctx context.Context, alias string) (bool, error) {
+	result, err := s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: aliasPrefix + alias},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting alias %q for %q from table %q: %w", alias, s.partition, s.table, err)
+	}
+
+	existed := len(result.Attributes) > 0
+	return existed, nil
+}
+
+// ListAway obtains the options that are away for this Store's partition.
+func (s Store) ListAway(ctx context.Context) (map[string]time.Time, error) {
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(
+			expression.KeyEqual(
+				expression.Key(partitionKey), expression.Value(s.partition),
+			).And(
+				expression.KeyBeginsWith(expression.Key(groupKey), awayPrefix),
+			),
+		).
+		WithProjection(expression.NamesList(
+			expression.Name(groupKey), expression.Name(untilKey),
+		)).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("building expression: %w", err)
+	}
+
+	result, err := s.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 &s.table,
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing away options for %q from table %q: %w", s.partition, s.table, err)
+	}
+
+	away := make(map[string]time.Time, len(result.Items))
+	for _, item := range result.Items {
+		option, ok := item[groupKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T in away options", item[groupKey])
+		}
+		until, ok := item[untilKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T in away times", item[untilKey])
+		}
+		t, err := time.Parse(time.RFC3339, until.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid away time: %w", err)
+		}
+		away[strings.TrimPrefix(option.Value, awayPrefix)] = t
+	}
+	return away, nil
+}
+
+// PutAway records that an option is away until the provided time in this
+// Store's partition.
+func (s Store) PutAway(ctx context.Context, option string, until time.Time) error {
+	_, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: awayPrefix + option},
+			untilKey:     &types.AttributeValueMemberS{Value: until.UTC().Format(time.RFC3339)},
+			expiresKey:   &types.AttributeValueMemberN{Value: strconv.FormatInt(until.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("saving away option %q for %q to table %q: %w", option, s.partition, s.table, err)
+	}
+
+	return nil
+}
+
+// DeleteAway removes the record of an away option from this Store's partition.
+func (s Store) DeleteAway(ctx context.Context, option string) (bool, error) {
+	result, err := s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: awayPrefix + option},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting away option %q for %q from table %q: %w", option, s.partition, s.table, err)
+	}
+
+	existed := len(result.Attributes) > 0
+	return existed, nil
+}
+
+// GetBotToken obtains the Slack bot token saved in this Store's partition, or
+// returns an empty string if none is saved.
+func (s Store) GetBotToken(ctx context.Context) (string, error) {
+	result, err := s.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: botTokenGroup},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting bot token for %q from table %q: %w", s.partition, s.table, err)
+	}
+
+	if len(result.Item) == 0 {
+		return "", nil
+	}
+
+	v, ok := result.Item[tokenKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", fmt.Errorf("invalid type %T in bot token", result.Item[tokenKey])
+	}
+
+	return v.Value, nil
+}
+
+// PutBotToken saves a Slack bot token into this Store's partition.
+func (s Store) PutBotToken(ctx context.Context, token string) error {
+	_, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: botTokenGroup},
+			tokenKey:     &types.AttributeValueMemberS{Value: token},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("saving bot token for %q to table %q: %w", s.partition, s.table, err)
+	}
+
+	return nil
+}
+
+// DeleteBotToken removes the Slack bot token from this Store's partition.
+func (s Store) DeleteBotToken(ctx context.Context) (bool, error) {
+	result, err := s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: botTokenGroup},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting bot token for %q from table %q: %w", s.partition, s.table, err)
+	}
+
+	existed := len(result.Attributes) > 0
+	return existed, nil
+}
+
+// ListPartitions obtains the distinct partition keys in the table. As DynamoDB
+// can only find these by scanning the entire table, this is much more
+// expensive than any other Store operation.
+func (s Store) ListPartitions(ctx context.Context) ([]string, error) {
+	expr, err := expression.NewBuilder().
+		WithProjection(expression.NamesList(
+			expression.Name(partitionKey),
+		)).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("building expression: %w", err)
+	}
+
+	var (
+		partitions []string
+		seen       = make(map[string]bool)
+	)
+	paginator := dynamodb.NewScanPaginator(s.db, &dynamodb.ScanInput{
+		TableName:                &s.table,
+		ProjectionExpression:     expr.Projection(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("scanning partitions from table %q: %w", s.table, err)
+		}
+		for _, item := range page.Items {
+			v, ok := item[partitionKey].(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, fmt.Errorf("invalid type %T in partition keys", item[partitionKey])
+			}
+			if !seen[v.Value] {
+				seen[v.Value] = true
+				partitions = append(partitions, v.Value)
+			}
+		}
+	}
+	return partitions, nil
+}
+
+// GetRateLimit obtains the state of the named Slack rate limit bucket from
+// this Store's partition.
+func (s Store) GetRateLimit(ctx context.Context, key string) (float64, time.Time, error) {
+	result, err := s.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: &s.table,
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: rateLimitPrefix + key},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("getting rate limit %q for %q from table %q: %w", key, s.partition, s.table, err)
+	}
+
+	if len(result.Item) == 0 {
+		return 0, time.Time{}, nil
+	}
+
+	tokensValue, ok := result.Item[tokensKey].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("invalid type %T in rate limit tokens", result.Item[tokensKey])
+	}
+	tokens, err := strconv.ParseFloat(tokensValue.Value, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("decoding rate limit tokens: %w", err)
+	}
+
+	atValue, ok := result.Item[atKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("invalid type %T in rate limit time", result.Item[atKey])
+	}
+	at, err := time.Parse(time.RFC3339Nano, atValue.Value)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("decoding rate limit time: %w", err)
+	}
+
+	return tokens, at, nil
+}
+
+// PutRateLimit saves the state of the named Slack rate limit bucket into this
+// Store's partition, if its previous state was counted at prevAt.
+func (s Store) PutRateLimit(ctx context.Context, key string, tokens float64, at, prevAt, expires time.Time) (bool, error) {
+	condition := expression.AttributeNotExists(expression.Name(atKey))
+	if !prevAt.IsZero() {
+		condition = expression.Equal(expression.Name(atKey), expression.Value(prevAt.UTC().Format(time.RFC3339Nano)))
+	}
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return false, fmt.Errorf("building expression: %w", err)
+	}
+
+	_, err = s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{Value: s.partition},
+			groupKey:     &types.AttributeValueMemberS{Value: rateLimitPrefix + key},
+			tokensKey:    &types.AttributeValueMemberN{Value: strconv.FormatFloat(tokens, 'g', -1, 64)},
+			atKey:        &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339Nano)},
+			expiresKey:   &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.Unix(), 10)},
+		},
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("saving rate limit %q for %q to table %q: %w", key, s.partition, s.table, err)
+	}
+
+	return true, nil
+}
@@ -4,58 +4,157 @@ package firestore
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/googleapis/gax-go/v2/apierror"
 	"google.golang.org/grpc/codes"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
 )
 
+// Store is a store backed by a Google Cloud Firestore database.
+//
+// Each Store keeps its groups in the collection named by its partition, with
+// one document per group holding its options and metadata. Aliases are stored
+// as documents in the same collection, with the name of the target group in an
+// "aliasOf" field.
+//
+// Everything else is stored in subcollections of the "Meta" document in the
+// partition's collection, which can never hold a group (see metaParentID).
+// Options that are away are stored in an "away" subcollection, where each
+// document holds the option in an "option" field and the time at which it
+// returns in an "until" field. A Slack bot token, if any, is stored in the
+// "token" field of the "bot" document in an "installation" subcollection. The
+// state of each Slack rate limit bucket is stored in a "ratelimit"
+// subcollection, with the number of tokens in a "tokens" field, the time at
+// which they were counted in an "at" field, and the time at which the bucket
+// refills completely in an "expires" field. You can create a [TTL policy] on
+// the "expires" field of the "ratelimit" collection group to have Firestore
+// clean up these documents.
+//
+// [TTL policy]: https://cloud.google.com/firestore/docs/ttl
 type Store struct {
 	client    *firestore.Client
 	partition string
 }
 
-type optionsDoc struct {
-	Options []string `firestore:"options"`
+type groupDoc struct {
+	Options     []string  `firestore:"options"`
+	Description string    `firestore:"description,omitempty"`
+	Tags        []string  `firestore:"tags,omitempty"`
+	Creator     string    `firestore:"creator,omitempty"`
+	Created     time.Time `firestore:"created,omitempty"`
+	Updated     time.Time `firestore:"updated,omitempty"`
+}
+
+type aliasDoc struct {
+	AliasOf string `firestore:"aliasOf"`
+}
+
+const aliasOfField = "aliasOf"
+
+// metaParentID is the ID of the document whose subcollections hold everything
+// in a partition but its groups and aliases. Groups and aliases are saved under
+// normalized names, which are case folded, so a document ID with an uppercase
+// letter can never belong to one.
+const metaParentID = "Meta"
+
+type awayDoc struct {
+	Option string    `firestore:"option"`
+	Until  time.Time `firestore:"until"`
+}
+
+const awayCollectionID = "away"
+
+type botTokenDoc struct {
+	Token string `firestore:"token"`
+}
+
+const (
+	installationCollectionID = "installation"
+	botTokenDocID            = "bot"
+)
+
+type rateLimitDoc struct {
+	Tokens  float64   `firestore:"tokens"`
+	At      time.Time `firestore:"at"`
+	Expires time.Time `firestore:"expires"`
 }
 
+const rateLimitCollectionID = "ratelimit"
+
 func New(client *firestore.Client, partition string) Store {
 	return Store{client, partition}
 }
 
 func (f Store) List(ctx context.Context) ([]string, error) {
-	refs := f.client.Collection(f.partition).DocumentRefs(ctx)
-	allRefs, err := refs.GetAll()
+	docs, err := f.client.Collection(f.partition).Select(aliasOfField).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, fmt.Errorf("listing collection: %w", err)
 	}
 
-	result := make([]string, len(allRefs))
-	for i, ref := range allRefs {
-		result[i] = ref.ID
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if _, isAlias := doc.Data()[aliasOfField]; !isAlias {
+			result = append(result, doc.Ref.ID)
+		}
 	}
 	return result, nil
 }
 
 func (f Store) Get(ctx context.Context, group string) ([]string, error) {
+	result, err := f.GetGroup(ctx, group)
+	return result.Options, err
+}
+
+func (f Store) Put(ctx context.Context, group string, options []string) error {
+	return f.PutGroup(ctx, group, randomizer.Group{Options: options})
+}
+
+func (f Store) ListGroups(ctx context.Context) (map[string]randomizer.Group, error) {
+	docs, err := f.client.Collection(f.partition).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("listing collection: %w", err)
+	}
+
+	result := make(map[string]randomizer.Group, len(docs))
+	for _, doc := range docs {
+		if _, isAlias := doc.Data()[aliasOfField]; isAlias {
+			continue
+		}
+		var group groupDoc
+		if err := doc.DataTo(&group); err != nil {
+			return nil, fmt.Errorf("decoding document: %w", err)
+		}
+		result[doc.Ref.ID] = randomizer.Group(group)
+	}
+	return result, nil
+}
+
+func (f Store) GetGroup(ctx context.Context, group string) (randomizer.Group, error) {
 	ref := f.client.Collection(f.partition).Doc(group)
 	doc, err := ref.Get(ctx)
+	if isNotFound(err) {
+		return randomizer.Group{}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("getting document: %w", err)
+		return randomizer.Group{}, fmt.Errorf("getting document: %w", err)
 	}
 
-	var result optionsDoc
+	var result groupDoc
 	err = doc.DataTo(&result)
 	if err != nil {
-		return nil, fmt.Errorf("decoding document: %w", err)
+		return randomizer.Group{}, fmt.Errorf("decoding document: %w", err)
 	}
 
-	return result.Options, nil
+	return randomizer.Group(result), nil
 }
 
-func (f Store) Put(ctx context.Context, group string, options []string) error {
-	ref := f.client.Collection(f.partition).Doc(group)
-	_, err := ref.Set(ctx, optionsDoc{options})
+func (f Store) PutGroup(ctx context.Context, name string, group randomizer.Group) error {
+	ref := f.client.Collection(f.partition).Doc(name)
+	_, err := ref.Set(ctx, groupDoc(group))
 	return err
 }
 
@@ -63,8 +162,91 @@ func (f Store) Delete(ctx context.Context, group string) (bool, error) {
 	ref := f.client.Collection(f.partition).Doc(group)
 	_, err := ref.Delete(ctx, firestore.Exists)
 
-	apiErr, ok := apierror.FromError(err)
-	if ok && apiErr.GRPCStatus().Code() == codes.NotFound {
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f Store) ListAliases(ctx context.Context) (map[string]string, error) {
+	docs, err := f.client.Collection(f.partition).Where(aliasOfField, ">", "").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases: %w", err)
+	}
+
+	result := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		var alias aliasDoc
+		if err := doc.DataTo(&alias); err != nil {
+			return nil, fmt.Errorf("decoding alias document: %w", err)
+		}
+		result[doc.Ref.ID] = alias.AliasOf
+	}
+	return result, nil
+}
+
+func (f Store) GetAlias(ctx context.Context, alias string) (string, error) {
+	ref := f.client.Collection(f.partition).Doc(alias)
+	doc, err := ref.Get(ctx)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting document: %w", err)
+	}
+
+	var result aliasDoc
+	err = doc.DataTo(&result)
+	if err != nil {
+		return "", fmt.Errorf("decoding document: %w", err)
+	}
+
+	return result.AliasOf, nil
+}
+
+func (f Store) PutAlias(ctx context.Context, alias, group string) error {
+	ref := f.client.Collection(f.partition).Doc(alias)
+	_, err := ref.Set(ctx, aliasDoc{group})
+	return err
+}
+
+func (f Store) DeleteAlias(ctx context.Context, alias string) (bool, error) {
+	// Aliases and groups share a collection, and the randomizer never gives an
+	// alias the name of a group, so this is just like deleting a group.
+	return f.Delete(ctx, alias)
+}
+
+func (f Store) ListAway(ctx context.Context) (map[string]time.Time, error) {
+	docs, err := f.awayCollection().Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("listing away options: %w", err)
+	}
+
+	result := make(map[string]time.Time, len(docs))
+	for _, doc := range docs {
+		var away awayDoc
+		if err := doc.DataTo(&away); err != nil {
+			return nil, fmt.Errorf("decoding away document: %w", err)
+		}
+		result[away.Option] = away.Until
+	}
+	return result, nil
+}
+
+func (f Store) PutAway(ctx context.Context, option string, until time.Time) error {
+	ref := f.awayCollection().Doc(awayDocID(option))
+	_, err := ref.Set(ctx, awayDoc{option, until})
+	return err
+}
+
+func (f Store) DeleteAway(ctx context.Context, option string) (bool, error) {
+	ref := f.awayCollection().Doc(awayDocID(option))
+	_, err := ref.Delete(ctx, firestore.Exists)
+
+	if isNotFound(err) {
 		return false, nil
 	}
 	if err != nil {
@@ -72,3 +254,115 @@ func (f Store) Delete(ctx context.Context, group string) (bool, error) {
 	}
 	return true, nil
 }
+
+func (f Store) awayCollection() *firestore.CollectionRef {
+	return f.client.Collection(f.partition).Doc(metaParentID).Collection(awayCollectionID)
+}
+
+func (f Store) GetBotToken(ctx context.Context) (string, error) {
+	doc, err := f.botTokenRef().Get(ctx)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var token botTokenDoc
+	if err := doc.DataTo(&token); err != nil {
+		return "", fmt.Errorf("decoding bot token document: %w", err)
+	}
+	return token.Token, nil
+}
+
+func (f Store) PutBotToken(ctx context.Context, token string) error {
+	_, err := f.botTokenRef().Set(ctx, botTokenDoc{token})
+	return err
+}
+
+func (f Store) DeleteBotToken(ctx context.Context) (bool, error) {
+	_, err := f.botTokenRef().Delete(ctx, firestore.Exists)
+
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f Store) botTokenRef() *firestore.DocumentRef {
+	return f.client.Collection(f.partition).Doc(metaParentID).Collection(installationCollectionID).Doc(botTokenDocID)
+}
+
+func (f Store) GetRateLimit(ctx context.Context, key string) (float64, time.Time, error) {
+	doc, err := f.rateLimitRef(key).Get(ctx)
+	if isNotFound(err) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var bucket rateLimitDoc
+	if err := doc.DataTo(&bucket); err != nil {
+		return 0, time.Time{}, fmt.Errorf("decoding rate limit document: %w", err)
+	}
+	return bucket.Tokens, bucket.At, nil
+}
+
+func (f Store) PutRateLimit(ctx context.Context, key string, tokens float64, at, prevAt, expires time.Time) (saved bool, err error) {
+	ref := f.rateLimitRef(key)
+	err = f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		saved = false // The transaction may run more than once.
+
+		var current rateLimitDoc
+		doc, err := tx.Get(ref)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&current); err != nil {
+				return fmt.Errorf("decoding rate limit document: %w", err)
+			}
+		}
+		if !current.At.Equal(prevAt) {
+			return nil
+		}
+
+		saved = true
+		return tx.Set(ref, rateLimitDoc{Tokens: tokens, At: at, Expires: expires})
+	})
+	return
+}
+
+func (f Store) rateLimitRef(key string) *firestore.DocumentRef {
+	return f.client.Collection(f.partition).Doc(metaParentID).Collection(rateLimitCollectionID).Doc(url.PathEscape(key))
+}
+
+// ListPartitions returns the IDs of the database's top-level collections,
+// each of which holds the groups of one partition.
+func (f Store) ListPartitions(ctx context.Context) ([]string, error) {
+	refs, err := f.client.Collections(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+
+	partitions := make([]string, len(refs))
+	for i, ref := range refs {
+		partitions[i] = ref.ID
+	}
+	return partitions, nil
+}
+
+// awayDocID escapes an option for use as a document ID, which can't contain
+// slashes.
+func awayDocID(option string) string {
+	return url.PathEscape(option)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := apierror.FromError(err)
+	return ok && apiErr.GRPCStatus().Code() == codes.NotFound
+}
@@ -0,0 +1,186 @@
+// Package teams supports invoking the randomizer through a Microsoft Teams
+// outgoing webhook.
+package teams
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/markdown"
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/webhook"
+)
+
+// App serves the randomizer as a Teams outgoing webhook, which users invoke by
+// mentioning the webhook in a channel, as in "@Randomizer one two three".
+//
+// App confirms the legitimacy of requests from Teams by checking their HMAC
+// signatures with the webhook's security token.
+type App struct {
+	// SecurityToken is the key that Teams signs requests with, decoded from the
+	// base64 form that Teams shows when creating the webhook.
+	SecurityToken []byte
+	// StoreFactory provides a Store for the Teams conversation in which the
+	// request was made. Partitions are named by the conversation ID, prefixed
+	// with "teams:" to keep them apart from those of other frontends.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// SecurityTokenFromEnv returns the security token in TEAMS_SECURITY_TOKEN, in
+// the base64 form that Teams shows when creating the webhook. If it is unset,
+// SecurityTokenFromEnv returns a nil token and a nil error, as Teams support is
+// optional.
+func SecurityTokenFromEnv() ([]byte, error) {
+	env, ok := os.LookupEnv("TEAMS_SECURITY_TOKEN")
+	if !ok {
+		return nil, nil
+	}
+	token, err := base64.StdEncoding.DecodeString(env)
+	if err != nil || len(token) == 0 {
+		return nil, errors.New("TEAMS_SECURITY_TOKEN is not a base64-encoded security token")
+	}
+	return token, nil
+}
+
+// activity represents a message activity that Teams sends to an outgoing
+// webhook. It includes only the fields that the randomizer uses.
+type activity struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	From struct {
+		Name string `json:"name"`
+	} `json:"from"`
+	Conversation struct {
+		ID string `json:"id"`
+	} `json:"conversation"`
+}
+
+// reply represents the message activity that App returns to Teams.
+type reply struct {
+	Type       string `json:"type"`
+	Text       string `json:"text"`
+	TextFormat string `json:"textFormat"`
+}
+
+// ServeHTTP serves POST requests from Teams.
+func (a App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Add("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhook.MaxBodySize))
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to read request body")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !a.isSignatureValid(r.Header, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var act activity
+	if err := json.Unmarshal(body, &act); err != nil {
+		webhook.LogError(a.Logger, err, "Failed to decode activity")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if act.Type != "message" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	webhook.WriteJSON(w, a.Logger, reply{
+		Type:       "message",
+		Text:       markdown.FromMrkdwn(a.handleMessage(r.Context(), act)),
+		TextFormat: "markdown",
+	})
+}
+
+// isSignatureValid checks the Authorization header of a request, which holds
+// an HMAC-SHA256 signature of the raw request body.
+func (a App) isSignatureValid(header http.Header, body []byte) (ok bool) {
+	signature, found := strings.CutPrefix(header.Get("Authorization"), "HMAC ")
+	if !found {
+		return false
+	}
+	gotMAC, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(a.SecurityToken) == 0 {
+		return false
+	}
+
+	subtle.WithDataIndependentTiming(func() {
+		mac := hmac.New(sha256.New, a.SecurityToken)
+		mac.Write(body)
+		ok = hmac.Equal(gotMAC, mac.Sum(nil))
+	})
+	return
+}
+
+// handleMessage runs the randomizer with the text of a verified message, and
+// returns the message to reply with.
+func (a App) handleMessage(ctx context.Context, act activity) string {
+	name, args := parseText(act.Text)
+	options := []randomizer.Option{randomizer.WithRules(a.Rules)}
+	if act.From.Name != "" {
+		options = append(options, randomizer.WithUser(act.From.Name))
+	}
+
+	app := randomizer.NewApp(name, a.StoreFactory(partition(act.Conversation.ID)), options...)
+	result, err := app.Main(ctx, args)
+	if err != nil {
+		webhook.LogError(a.Logger, err, "Failed to run randomizer")
+		return webhook.HelpText(err)
+	}
+	return result.Message()
+}
+
+var (
+	// leadingMentionPattern matches the mention of the webhook at the start of
+	// the text of a message.
+	leadingMentionPattern = regexp.MustCompile(`^\s*<at>([^<]*)</at>`)
+	// tagPattern matches the HTML tags that Teams may include in the text of a
+	// message.
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parseText returns the name that a message used to mention the webhook, and
+// the arguments that follow the mention.
+func parseText(text string) (name string, args []string) {
+	name = "@Randomizer"
+	if match := leadingMentionPattern.FindStringSubmatchIndex(text); match != nil {
+		// Help text that refers to the webhook by its mention reads naturally in
+		// Teams.
+		name = "@" + html.UnescapeString(text[match[2]:match[3]])
+		text = text[match[1]:]
+	}
+	text = html.UnescapeString(tagPattern.ReplaceAllString(text, " "))
+	return name, strings.Fields(text)
+}
+
+// partition returns the partition for the groups of a conversation. Messages
+// in a channel thread carry the ID of the thread's first message, which we
+// drop so that the whole channel shares its groups.
+func partition(conversationID string) string {
+	channel, _, _ := strings.Cut(conversationID, ";")
+	return "teams:" + channel
+}
@@ -0,0 +1,110 @@
+package teams
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+var testToken = []byte("test security token")
+
+func signedRequest(token []byte, body string) *http.Request {
+	mac := hmac.New(sha256.New, token)
+	mac.Write([]byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "HMAC "+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func messageBody(text string) string {
+	body, _ := json.Marshal(map[string]any{
+		"type":         "message",
+		"text":         text,
+		"from":         map[string]string{"name": "Alice"},
+		"conversation": map[string]string{"id": "19:abc@thread.skype;messageid=123"},
+	})
+	return string(body)
+}
+
+func TestTeams(t *testing.T) {
+	stores := new(rndtest.Partitions)
+	app := App{
+		SecurityToken: testToken,
+		StoreFactory:  func(partition string) randomizer.Store { return stores.Store(partition) },
+	}
+
+	testCases := []struct {
+		description string
+		req         *http.Request
+		wantStatus  int
+		wantText    string
+	}{
+		{
+			description: "saving a group",
+			req:         signedRequest(testToken, messageBody("<at>Randomizer</at>&nbsp;/save snacks chips&amp;dip pretzels\n")),
+			wantStatus:  http.StatusOK,
+			wantText:    `The "snacks" group was saved`,
+		},
+		{
+			description: "using a group",
+			req:         signedRequest(testToken, messageBody("<at>Randomizer</at> snacks")),
+			wantStatus:  http.StatusOK,
+			wantText:    "**chips&dip**",
+		},
+		{
+			description: "help",
+			req:         signedRequest(testToken, messageBody("<at>Picker</at> help")),
+			wantStatus:  http.StatusOK,
+			wantText:    "@Picker randomizes the order of options",
+		},
+		{
+			description: "wrong token",
+			req:         signedRequest([]byte("wrong"), messageBody("<at>Randomizer</at> one two")),
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			description: "missing signature",
+			req:         httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(messageBody("one two"))),
+			wantStatus:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, tc.req)
+			if resp.Code != tc.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.Code, tc.wantStatus)
+			}
+			if tc.wantText == "" {
+				return
+			}
+
+			var body reply
+			if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Type != "message" || !strings.Contains(body.Text, tc.wantText) {
+				t.Errorf("got reply %+v, want text containing %q", body, tc.wantText)
+			}
+		})
+	}
+
+	want := map[string]rndtest.Store{
+		"teams:19:abc@thread.skype": {"snacks": {"chips&dip", "pretzels"}},
+	}
+	if got := stores.Stores(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected stores\ngot:  %v\nwant: %v", got, want)
+	}
+}
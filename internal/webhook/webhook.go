@@ -0,0 +1,56 @@
+// Package webhook holds the HTTP plumbing that the randomizer's chat service
+// frontends share.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// MaxBodySize limits the size of the request bodies that frontends read. Chat
+// services' requests are far smaller than this.
+const MaxBodySize = 1 << 20
+
+// fallbackHelpText is shown in place of errors that don't explain themselves
+// to users.
+const fallbackHelpText = "Whoops, something went wrong. Please try again later!"
+
+// HelpText returns the user-friendly help text of an error from the
+// randomizer, like that of a randomizer.Error. For errors without help text,
+// such as those from a canceled context, it returns a generic apology.
+func HelpText(err error) string {
+	var herr interface{ HelpText() string }
+	if errors.As(err, &herr) {
+		return herr.HelpText()
+	}
+	return fallbackHelpText
+}
+
+// WriteJSON writes body as a JSON response. Chat services render messages
+// from the raw text, so it leaves HTML characters unescaped. It logs any
+// failure to logger, if non-nil.
+func WriteJSON(w http.ResponseWriter, logger *slog.Logger, body any) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		LogError(logger, err, "Failed to encode response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		LogError(logger, err, "Failed to write response")
+	}
+}
+
+// LogError logs an error with a message and optional attributes, if logger is
+// non-nil.
+func LogError(logger *slog.Logger, err error, msg string, args ...any) {
+	if logger != nil {
+		logger.Error(msg, append([]any{"err", err}, args...)...)
+	}
+}
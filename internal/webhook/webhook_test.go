@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type helpfulError struct{}
+
+func (helpfulError) Error() string    { return "helpful error" }
+func (helpfulError) HelpText() string { return "Whoops, that didn't work." }
+
+func TestHelpText(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{helpfulError{}, "Whoops, that didn't work."},
+		{fmt.Errorf("wrapped: %w", helpfulError{}), "Whoops, that didn't work."},
+		{context.Canceled, fallbackHelpText},
+	}
+	for _, tc := range testCases {
+		if got := HelpText(tc.err); got != tc.want {
+			t.Errorf("HelpText(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	resp := httptest.NewRecorder()
+	WriteJSON(resp, nil, map[string]string{"text": "<@U1> & friends"})
+	if got := resp.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", got)
+	}
+	if got, want := strings.TrimSpace(resp.Body.String()), `{"text":"<@U1> & friends"}`; got != want {
+		t.Errorf("got body %s, want %s", got, want)
+	}
+}
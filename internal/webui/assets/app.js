@@ -0,0 +1,218 @@
+// The randomizer's web interface. Everything is rendered with DOM methods and
+// textContent, never innerHTML, so that group names and options can't inject
+// markup.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+let currentPartition = "";
+let editingGroup = "";
+
+// api calls the REST API, and returns the decoded response body. It throws an
+// Error with the API's explanation when the request fails.
+async function api(method, path, body) {
+  const init = {
+    method,
+    headers: { "X-Randomizer-UI": "1" },
+  };
+  if (body !== undefined) {
+    init.headers["Content-Type"] = "application/json";
+    init.body = JSON.stringify(body);
+  }
+  const resp = await fetch("api/v1" + path, init);
+  const data = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    const err = new Error(data.error || `The request failed with status ${resp.status}.`);
+    err.status = resp.status;
+    throw err;
+  }
+  return data;
+}
+
+function partitionPath(partition) {
+  return "/partitions/" + encodeURIComponent(partition);
+}
+
+function groupPath(name) {
+  return partitionPath(currentPartition) + "/groups/" + encodeURIComponent(name);
+}
+
+function showError(err) {
+  $("error").textContent = err ? err.message : "";
+  $("error").hidden = !err;
+}
+
+// run performs an action, showing any error that it throws.
+async function run(action) {
+  showError(null);
+  try {
+    await action();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function button(label, className, onClick) {
+  const b = document.createElement("button");
+  b.type = "button";
+  b.textContent = label;
+  if (className) {
+    b.className = className;
+  }
+  b.addEventListener("click", () => run(onClick));
+  return b;
+}
+
+function parseOptions(text) {
+  return text
+    .split("\n")
+    .map((line) => line.trim())
+    .filter((line) => line !== "");
+}
+
+async function loadPartitions() {
+  const list = $("partition-list");
+  const note = $("partition-note");
+  try {
+    const { partitions } = await api("GET", "/partitions");
+    list.replaceChildren(
+      ...partitions.map((partition) => {
+        const li = document.createElement("li");
+        li.append(button(partition, "link", () => openPartition(partition)));
+        return li;
+      }),
+    );
+    note.hidden = partitions.length > 0;
+    note.textContent = "No partitions have saved data yet.";
+  } catch (err) {
+    list.replaceChildren();
+    note.hidden = false;
+    note.textContent =
+      err.status === 501
+        ? "This storage backend can't list partitions, so open one by name."
+        : err.message;
+  }
+}
+
+async function openPartition(partition) {
+  currentPartition = partition;
+  if (decodeURIComponent(location.hash.slice(1)) !== partition) {
+    history.replaceState(null, "", "#" + encodeURIComponent(partition));
+  }
+  $("partition-name").textContent = partition;
+  $("welcome").hidden = true;
+  $("groups").hidden = false;
+  $("quick-select").hidden = false;
+  $("editor").hidden = true;
+  $("result").hidden = true;
+  await loadGroups();
+}
+
+async function loadGroups() {
+  const { groups } = await api("GET", partitionPath(currentPartition) + "/groups");
+  $("group-list").replaceChildren(
+    ...groups.map((name) => {
+      const li = document.createElement("li");
+      const label = document.createElement("span");
+      label.textContent = name;
+      li.append(
+        label,
+        button("Randomize", "", () => select({ group: name })),
+        button("Edit", "", () => editGroup(name)),
+      );
+      return li;
+    }),
+  );
+  $("no-groups").hidden = groups.length > 0;
+}
+
+async function editGroup(name) {
+  const group = name ? await api("GET", groupPath(name)) : { name: "", options: [] };
+  editingGroup = group.name;
+  $("editor-title").textContent = name ? `Edit “${group.name}”` : "New group";
+  $("group-name").value = group.name;
+  $("group-options").value = group.options.join("\n");
+  $("delete-group").hidden = !name;
+  $("editor").hidden = false;
+  $("group-name").focus();
+}
+
+async function saveGroup() {
+  const name = $("group-name").value.trim();
+  const group = await api("PUT", groupPath(name), {
+    options: parseOptions($("group-options").value),
+  });
+  // Saving under a new name leaves the old group in place, as with the
+  // randomizer's /save flag.
+  editingGroup = group.name;
+  $("editor").hidden = true;
+  showResult([], group.message);
+  await loadGroups();
+}
+
+async function deleteGroup() {
+  if (!confirm(`Delete the “${editingGroup}” group?`)) {
+    return;
+  }
+  const deleted = await api("DELETE", groupPath(editingGroup));
+  $("editor").hidden = true;
+  showResult([], deleted.message);
+  await loadGroups();
+}
+
+async function select(input) {
+  const selection = await api("POST", partitionPath(currentPartition) + "/select", input);
+  showResult(selection.options, selection.message);
+}
+
+// plainText renders a message from the API, which uses Slack's formatting, as
+// plain text: without the asterisks around bold text, and with HTML entities
+// decoded.
+function plainText(message) {
+  return message
+    .replace(/\*([^*\n]+)\*/g, "$1")
+    .replaceAll("&lt;", "<")
+    .replaceAll("&gt;", ">")
+    .replaceAll("&amp;", "&");
+}
+
+function showResult(options, message) {
+  $("result-list").replaceChildren(
+    ...options.map((option) => {
+      const li = document.createElement("li");
+      li.textContent = option;
+      return li;
+    }),
+  );
+  $("result-message").textContent = plainText(message);
+  $("result").hidden = false;
+}
+
+function init() {
+  $("partition-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const partition = $("partition-input").value.trim();
+    run(() => openPartition(partition));
+  });
+  $("new-group").addEventListener("click", () => run(() => editGroup("")));
+  $("editor-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(saveGroup);
+  });
+  $("delete-group").addEventListener("click", () => run(deleteGroup));
+  $("cancel-edit").addEventListener("click", () => {
+    $("editor").hidden = true;
+  });
+  $("quick-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(() => select({ options: parseOptions($("quick-options").value) }));
+  });
+
+  run(loadPartitions);
+  const partition = decodeURIComponent(location.hash.slice(1));
+  if (partition) {
+    run(() => openPartition(partition));
+  }
+}
+
+init();
@@ -0,0 +1,155 @@
+// Package webui serves a small browser interface for managing the randomizer's
+// groups and running selections.
+//
+// The interface is a static page, embedded in the binary, that calls the JSON
+// REST API of the httpapi package. It renders everything in the browser, so
+// that no binary needs Go's template packages to serve it.
+package webui
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/featherbread/randomizer/internal/httpapi"
+	"github.com/featherbread/randomizer/internal/randomizer"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Auth configures how the web interface authenticates users.
+type Auth struct {
+	// Password, if set, is the admin password that users must enter through
+	// HTTP Basic authentication. The username that they enter is recorded as the
+	// creator of the groups that they save.
+	Password string
+	// UserHeader, if set, is the name of a request header that a reverse proxy
+	// sets to the name of the authenticated user. The web interface trusts this
+	// header completely, so the proxy must strip it from incoming requests.
+	UserHeader string
+}
+
+// AuthFromEnv returns the web interface's authentication settings from
+// RANDOMIZER_UI_PASSWORD or RANDOMIZER_UI_USER_HEADER, only one of which may
+// be set. If neither is set, AuthFromEnv returns a zero Auth and a nil error,
+// as the web interface is optional.
+func AuthFromEnv() (Auth, error) {
+	auth := Auth{
+		Password:   os.Getenv("RANDOMIZER_UI_PASSWORD"),
+		UserHeader: os.Getenv("RANDOMIZER_UI_USER_HEADER"),
+	}
+	if auth.Password != "" && auth.UserHeader != "" {
+		return Auth{}, errors.New("only one of RANDOMIZER_UI_PASSWORD and RANDOMIZER_UI_USER_HEADER may be set")
+	}
+	return auth, nil
+}
+
+// Enabled reports whether the settings allow anyone to use the web interface.
+func (a Auth) Enabled() bool {
+	return a.Password != "" || a.UserHeader != ""
+}
+
+// App serves the web interface.
+type App struct {
+	// Auth configures how the web interface authenticates users. If it allows
+	// no one to use the interface, every request fails.
+	Auth Auth
+	// StoreFactory provides a Store for each partition that the interface
+	// manages. The interface can only list partitions if the stores implement
+	// ListPartitions, like the slack package's PartitionLister.
+	StoreFactory func(partition string) randomizer.Store
+	// Rules sets the limits that the randomizer enforces when saving groups.
+	Rules randomizer.Rules
+	// Logger, if non-nil, logs errors encountered during request handling.
+	Logger *slog.Logger
+}
+
+// Handler returns a handler for the web interface, which serves the page at /
+// and the REST API under /api/. Use [http.StripPrefix] to serve the interface
+// under another path.
+func (a App) Handler() http.Handler {
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err) // The embedded assets are fixed at build time.
+	}
+	api := httpapi.App{
+		Authenticate: func(r *http.Request) (string, bool) {
+			user, ok := r.Context().Value(userKey{}).(string)
+			return user, ok
+		},
+		StoreFactory: a.StoreFactory,
+		Rules:        a.Rules,
+		Logger:       a.Logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServerFS(static))
+	mux.Handle("/api/", requireUIHeader(http.StripPrefix("/api", api.Handler())))
+	return a.authenticate(mux)
+}
+
+type userKey struct{}
+
+// authenticate wraps a handler so that it only serves authenticated users.
+func (a App) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := a.user(r)
+		if !ok {
+			if a.Auth.Password != "" {
+				w.Header().Add("WWW-Authenticate", `Basic realm="Randomizer", charset="UTF-8"`)
+			}
+			http.Error(w, "You must sign in to use the randomizer.", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// user returns the name of the user making a request, if they are
+// authenticated.
+func (a App) user(r *http.Request) (user string, ok bool) {
+	switch {
+	case a.Auth.UserHeader != "":
+		user = strings.TrimSpace(r.Header.Get(a.Auth.UserHeader))
+		return user, user != ""
+
+	case a.Auth.Password != "":
+		user, password, found := r.BasicAuth()
+		if !found {
+			return "", false
+		}
+		subtle.WithDataIndependentTiming(func() {
+			ok = subtle.ConstantTimeCompare([]byte(password), []byte(a.Auth.Password)) == 1
+		})
+		if user == "" {
+			user = "admin"
+		}
+		return user, ok
+
+	default:
+		return "", false
+	}
+}
+
+// uiHeader is a header that the page sends with every API request. Browsers
+// won't send custom headers with cross-site requests unless the server allows
+// them through CORS, which we don't, so requiring the header keeps other sites
+// from using a signed-in user's credentials.
+const uiHeader = "X-Randomizer-UI"
+
+func requireUIHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(uiHeader) == "" {
+			http.Error(w, "Missing "+uiHeader+" header.", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
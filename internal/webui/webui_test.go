@@ -0,0 +1,117 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/featherbread/randomizer/internal/randomizer"
+	"github.com/featherbread/randomizer/internal/randomizer/rndtest"
+)
+
+func newTestHandler(auth Auth) (http.Handler, rndtest.GroupStore) {
+	store := rndtest.NewGroupStore()
+	app := App{
+		Auth:         auth,
+		StoreFactory: func(string) randomizer.Store { return store },
+	}
+	return http.StripPrefix("/ui", app.Handler()), store
+}
+
+func TestPasswordAuth(t *testing.T) {
+	handler, _ := newTestHandler(Auth{Password: "secret"})
+
+	testCases := []struct {
+		description string
+		user, pass  string
+		wantStatus  int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"wrong password", "alice", "wrong", http.StatusUnauthorized},
+		{"right password", "alice", "secret", http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ui/", nil)
+			if tc.pass != "" {
+				req.SetBasicAuth(tc.user, tc.pass)
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			if resp.Code != tc.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.Code, tc.wantStatus)
+			}
+			if resp.Code == http.StatusUnauthorized && !strings.HasPrefix(resp.Header().Get("WWW-Authenticate"), "Basic ") {
+				t.Errorf("missing Basic challenge: %v", resp.Header())
+			}
+			if resp.Code == http.StatusOK && !strings.Contains(resp.Body.String(), `<script src="app.js"`) {
+				t.Errorf("unexpected page:\n%s", resp.Body)
+			}
+		})
+	}
+}
+
+func TestAPIThroughUI(t *testing.T) {
+	handler, store := newTestHandler(Auth{UserHeader: "X-Forwarded-User"})
+
+	send := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+	signedIn := map[string]string{"X-Forwarded-User": "alice", uiHeader: "1"}
+
+	if resp := send(http.MethodGet, "/ui/api/v1/partitions/C1/groups", "", map[string]string{uiHeader: "1"}); resp.Code != http.StatusUnauthorized {
+		t.Errorf("request without user got status %d", resp.Code)
+	}
+	if resp := send(http.MethodGet, "/ui/api/v1/partitions/C1/groups", "", map[string]string{"X-Forwarded-User": "alice"}); resp.Code != http.StatusForbidden {
+		t.Errorf("request without UI header got status %d", resp.Code)
+	}
+
+	resp := send(http.MethodPut, "/ui/api/v1/partitions/C1/groups/snacks", `{"options":["chips","pretzels"]}`, signedIn)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("saving got status %d\n%s", resp.Code, resp.Body)
+	}
+	if got := store.Metadata["snacks"].Creator; got != "alice" {
+		t.Errorf("got creator %q, want %q", got, "alice")
+	}
+
+	resp = send(http.MethodGet, "/ui/api/v1/partitions/C1/groups", "", signedIn)
+	var list struct{ Groups []string }
+	if err := json.Unmarshal(resp.Body.Bytes(), &list); err != nil || len(list.Groups) != 1 || list.Groups[0] != "snacks" {
+		t.Errorf("unexpected group list (err %v):\n%s", err, resp.Body)
+	}
+
+	resp = send(http.MethodGet, "/ui/app.js", "", signedIn)
+	if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), uiHeader) {
+		t.Errorf("script got status %d, or doesn't send the %s header", resp.Code, uiHeader)
+	}
+}
+
+func TestNoAuth(t *testing.T) {
+	handler, _ := newTestHandler(Auth{})
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", resp.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthFromEnv(t *testing.T) {
+	t.Setenv("RANDOMIZER_UI_PASSWORD", "secret")
+	t.Setenv("RANDOMIZER_UI_USER_HEADER", "")
+	if auth, err := AuthFromEnv(); err != nil || auth != (Auth{Password: "secret"}) || !auth.Enabled() {
+		t.Errorf("got %+v, %v", auth, err)
+	}
+
+	t.Setenv("RANDOMIZER_UI_USER_HEADER", "X-Forwarded-User")
+	if _, err := AuthFromEnv(); err == nil {
+		t.Error("unexpected success with both settings")
+	}
+}